@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// apiReplyTimeout bounds how long a request waits for the TUI to answer.
+const apiReplyTimeout = 2 * time.Second
+
+// apiStatus is the session snapshot served at GET /state. Durations are in seconds.
+type apiStatus struct {
+	State     string         `json:"state"` // idle, ready, running, paused, break or stopped
+	Routine   string         `json:"routine,omitempty"`
+	Habit     string         `json:"habit,omitempty"`
+	Index     int            `json:"index"` // Current habit, counting from 1
+	Count     int            `json:"count"`
+	Elapsed   int            `json:"elapsed"`
+	Remaining int            `json:"remaining"`
+	Paused    bool           `json:"paused"`
+	Checklist []apiItem      `json:"checklist,omitempty"`
+	Events    []apiEventInfo `json:"events"`
+}
+
+type apiItem struct {
+	Text  string `json:"text"`
+	Done  bool   `json:"done"`
+	Depth int    `json:"depth,omitempty"`
+}
+
+type apiEventInfo struct {
+	Name string    `json:"name"`
+	Next time.Time `json:"next"`
+	In   int       `json:"in"` // Seconds until Next, negative once it has passed
+}
+
+// apiStateMsg asks the running program for a snapshot.
+type apiStateMsg struct {
+	reply chan apiStatus
+}
+
+// apiCommandMsg delivers a command from the API into Update.
+type apiCommandMsg struct {
+	command string // pause, resume, next or toggle
+	item    int    // Checklist index for toggle, counting from 0
+	reply   chan error
+}
+
+// apiStatus takes a snapshot of the session and the upcoming events.
+func (m model) apiStatus() apiStatus {
+	s := apiStatus{State: "idle", Events: []apiEventInfo{}}
+	switch m.state {
+	case stateReadyToStart, stateRoutineView:
+		s.State = "ready"
+	case stateRunning:
+		s.State = "running"
+	case statePaused:
+		s.State = "paused"
+		s.Paused = true
+	case statePausing:
+		s.State = "break"
+		s.Paused = true
+	case stateStopped:
+		s.State = "stopped"
+	case stateOutline:
+		s.State = "ready"
+		if m.outlineReturn == stateRunning || m.outlineReturn == statePaused {
+			s.State = "paused"
+			s.Paused = true
+		}
+	}
+
+	if s.State != "idle" && m.routineFileName != "" {
+		s.Routine = routineDisplayName(m.routineFileName)
+		s.Count = len(m.routines)
+		if m.current < len(m.routines) && s.State != "stopped" {
+			r := m.routines[m.current]
+			s.Habit = r.Title
+			s.Index = m.current + 1
+			elapsed := m.habitElapsed()
+			s.Elapsed = int(elapsed.Seconds())
+			s.Remaining = max(int((m.currentDuration() - elapsed).Seconds()), 0)
+			for _, item := range r.Checklist {
+				s.Checklist = append(s.Checklist, apiItem{Text: item.Text, Done: item.Complete, Depth: item.Depth})
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, u := range upcomingEvents(m.events, now) {
+		s.Events = append(s.Events, apiEventInfo{
+			Name: apiEventName(u.Event),
+			Next: u.Next,
+			In:   int(u.Next.Sub(now).Seconds()),
+		})
+	}
+	return s
+}
+
+// apiEventName is the name the API shows for an event. Secret events are only
+// ever shown by their code phrase, even after they were unlocked in the TUI.
+func apiEventName(e Event) string {
+	if e.CodePhrase != "" {
+		return e.CodePhrase
+	}
+	return e.Name
+}
+
+// apiCommand runs a command from the API as if its key had been pressed.
+func (m *model) apiCommand(msg apiCommandMsg) (tea.Cmd, error) {
+	switch msg.command {
+	case "pause":
+		if m.state != stateRunning {
+			return nil, fmt.Errorf("no habit is running")
+		}
+		m.pauseHabit()
+		return nil, nil
+
+	case "resume":
+		switch m.state {
+		case statePaused, statePausing:
+			return m.resumeHabit(), nil
+		case stateReadyToStart, stateRoutineView:
+			if len(m.routines) == 0 {
+				return nil, fmt.Errorf("no habits run today")
+			}
+			return m.startHabit(), nil
+		}
+		return nil, fmt.Errorf("nothing to resume")
+
+	case "next":
+		if m.state != stateRunning && m.state != statePaused {
+			return nil, fmt.Errorf("no habit is running")
+		}
+		m.finishHabit()
+		return nil, nil
+
+	case "toggle":
+		if m.state != stateRunning && m.state != statePaused {
+			return nil, fmt.Errorf("no habit is running")
+		}
+		list := m.routines[m.current].Checklist
+		if msg.item < 0 || msg.item >= len(list) {
+			return nil, fmt.Errorf("no checklist item %d", msg.item)
+		}
+		toggleItem(list, msg.item)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown command %q", msg.command)
+}
+
+// listenAPI opens the API listener. addr is a localhost address such as
+// "127.0.0.1:7878" or ":7878", or a Unix socket given as "unix:/path" or a path.
+func listenAPI(addr string) (net.Listener, error) {
+	if path, ok := apiSocketPath(addr); ok {
+		// A socket left behind by a previous run would make Listen fail. Only
+		// remove it when nothing answers on it, so a running timey keeps its socket.
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+				conn.Close()
+				return nil, fmt.Errorf("another program is already listening on %s", path)
+			}
+			os.Remove(path)
+		}
+		return net.Listen("unix", path)
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("the API only listens on localhost, not %q", host)
+	}
+	return net.Listen("tcp", net.JoinHostPort(host, port))
+}
+
+// apiSocketPath reports whether addr names a Unix socket and returns its path.
+func apiSocketPath(addr string) (string, bool) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		return path, true
+	}
+	if strings.HasPrefix(addr, "/") || strings.HasPrefix(addr, ".") || strings.HasSuffix(addr, ".sock") {
+		return addr, true
+	}
+	return "", false
+}
+
+// serveAPI answers HTTP requests on l, passing them to the program with Program.Send.
+func serveAPI(l net.Listener, p *tea.Program) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /state", func(w http.ResponseWriter, r *http.Request) {
+		status, err := askState(p)
+		if err != nil {
+			writeAPIError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeJSON(w, status)
+	})
+
+	mux.HandleFunc("GET /events", func(w http.ResponseWriter, r *http.Request) {
+		status, err := askState(p)
+		if err != nil {
+			writeAPIError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeJSON(w, status.Events)
+	})
+
+	mux.HandleFunc("POST /{command}", func(w http.ResponseWriter, r *http.Request) {
+		msg := apiCommandMsg{command: r.PathValue("command"), reply: make(chan error, 1)}
+		if msg.command == "toggle" {
+			item, err := strconv.Atoi(r.URL.Query().Get("item"))
+			if err != nil {
+				writeAPIError(w, http.StatusBadRequest, fmt.Errorf("toggle needs ?item=N"))
+				return
+			}
+			msg.item = item
+		}
+		p.Send(msg)
+		select {
+		case err := <-msg.reply:
+			if err != nil {
+				writeAPIError(w, http.StatusConflict, err)
+				return
+			}
+		case <-time.After(apiReplyTimeout):
+			writeAPIError(w, http.StatusServiceUnavailable, fmt.Errorf("timey did not answer"))
+			return
+		}
+		status, err := askState(p)
+		if err != nil {
+			writeAPIError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		writeJSON(w, status)
+	})
+
+	http.Serve(l, localOnly(mux, l.Addr().Network() == "tcp"))
+}
+
+// localOnly rejects requests that may come from a web page rather than a local
+// program: any request carrying an Origin header, which browsers add to
+// cross-site requests, and on TCP any request whose Host isn't localhost, which
+// stops DNS rebinding. Unix sockets can't be reached from a browser.
+func localOnly(next http.Handler, checkHost bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			writeAPIError(w, http.StatusForbidden, fmt.Errorf("requests from web pages are not allowed"))
+			return
+		}
+		if checkHost && !isLocalHost(r.Host) {
+			writeAPIError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLocalHost reports whether a Host header names this machine: localhost or a
+// loopback address, with or without a port.
+func isLocalHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// askState fetches a snapshot from the running program.
+func askState(p *tea.Program) (apiStatus, error) {
+	reply := make(chan apiStatus, 1)
+	p.Send(apiStateMsg{reply: reply})
+	select {
+	case s := <-reply:
+		return s, nil
+	case <-time.After(apiReplyTimeout):
+		return apiStatus{}, fmt.Errorf("timey did not answer")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLocalOnly(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name      string
+		host      string
+		origin    string
+		checkHost bool
+		want      int
+	}{
+		{"localhost", "localhost:7878", "", true, http.StatusOK},
+		{"loopback", "127.0.0.1:7878", "", true, http.StatusOK},
+		{"ipv6 loopback", "[::1]:7878", "", true, http.StatusOK},
+		{"rebound name", "evil.example:7878", "", true, http.StatusForbidden},
+		{"web page", "127.0.0.1:7878", "https://evil.example", true, http.StatusForbidden},
+		{"socket", "timey", "", false, http.StatusOK},
+		{"web page on socket", "timey", "null", false, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/pause", nil)
+		r.Host = tt.host
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		w := httptest.NewRecorder()
+		localOnly(ok, tt.checkHost).ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
+
+func TestAPIStatusHidesSecretNames(t *testing.T) {
+	m := model{events: []Event{
+		{Name: "Dentist", CodePhrase: "teeth", Sealed: sealedPrefix + "x", DateTime: time.Now().Add(time.Hour)},
+		{Name: "Standup", DateTime: time.Now().Add(2 * time.Hour)},
+	}}
+	events := m.apiStatus().Events
+	if len(events) != 2 || events[0].Name != "teeth" || events[1].Name != "Standup" {
+		t.Errorf("events = %+v, want the code phrase for the secret event", events)
+	}
+}
+
+func TestListenAPIKeepsLiveSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "timey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api.sock") // Short enough for the socket path limit
+
+	first, err := listenAPI("unix:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, err := listenAPI("unix:" + path); err == nil {
+		second.Close()
+		t.Fatal("a second listener took over the socket of a running one")
+	}
+
+	// A socket nobody answers on is stale and gets replaced.
+	first.(*net.UnixListener).SetUnlinkOnClose(false)
+	first.Close()
+	again, err := listenAPI("unix:" + path)
+	if err != nil {
+		t.Fatalf("stale socket was not replaced: %v", err)
+	}
+	again.Close()
+}
+
+func TestListenAPIRefusesOtherHosts(t *testing.T) {
+	if l, err := listenAPI("0.0.0.0:0"); err == nil {
+		l.Close()
+		t.Error("listenAPI accepted 0.0.0.0")
+	}
+	l, err := listenAPI("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+}
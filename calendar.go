@@ -0,0 +1,170 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// agendaDays is how far ahead the agenda view looks from the selected day.
+const agendaDays = 30
+
+// agendaItem is a single entry shown in the calendar and agenda views.
+type agendaItem struct {
+	When  time.Time
+	Title string
+	Kind  string // "event", "routine" or "habit"
+}
+
+// scheduledHabit is a habit tied to a time of day, together with the routine it belongs to.
+type scheduledHabit struct {
+	Routine string
+	Habit   Routine
+}
+
+// loadScheduledHabits reads every routine file in dir and returns the habits that have an At time.
+func loadScheduledHabits(dir string) []scheduledHabit {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var habits []scheduledHabit
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		routines, err := loadRoutines(filepath.Join(dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		name := routineDisplayName(file.Name())
+		for _, r := range routines {
+			if r.At != "" {
+				habits = append(habits, scheduledHabit{Routine: name, Habit: r})
+			}
+		}
+	}
+	return habits
+}
+
+// atTime returns the given time of day ("07:30") on day.
+func atTime(day time.Time, at string) (time.Time, bool) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), true
+}
+
+// startOfDay truncates t to local midnight.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dayOfWeek numbers weekday from 1 on weekStart to 7 on the day before it.
+func dayOfWeek(weekday, weekStart time.Weekday) int {
+	return (int(weekday)-int(weekStart)+7)%7 + 1
+}
+
+// startOfWeek returns midnight on the first day of the week containing t, for
+// weeks starting on weekStart.
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	return startOfDay(t).AddDate(0, 0, 1-dayOfWeek(t.Weekday(), weekStart))
+}
+
+// sameDay reports whether a and b fall on the same calendar date.
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// agendaBetween expands events, scheduled routines and timed habits into items in [from, to), sorted by time.
+func agendaBetween(events []Event, schedules []scheduledRoutine, habits []scheduledHabit, from, to time.Time) []agendaItem {
+	var items []agendaItem
+	for _, e := range events {
+		for _, t := range occurrencesBetween(e, from, to) {
+			items = append(items, agendaItem{When: t, Title: eventDisplayName(e), Kind: "event"})
+		}
+	}
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, r := range schedules {
+			t, ok := atTime(day, r.Schedule.At)
+			if !ok || !r.Schedule.runsOn(day.Weekday()) || t.Before(from) || !t.Before(to) {
+				continue
+			}
+			items = append(items, agendaItem{When: t, Title: r.DisplayName, Kind: "routine"})
+		}
+		for _, h := range habits {
+			t, ok := atTime(day, h.Habit.At)
+			if !ok || !h.Habit.runsOn(day.Weekday()) || t.Before(from) || !t.Before(to) {
+				continue
+			}
+			items = append(items, agendaItem{When: t, Title: h.Routine + ": " + h.Habit.Title, Kind: "habit"})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].When.Before(items[j].When) })
+	return items
+}
+
+// openCalendar switches to the month calendar with today selected.
+func (m *model) openCalendar() {
+	m.state = stateCalendar
+	m.calendarDay = startOfDay(time.Now())
+	m.scheduledHabits = loadScheduledHabits("routines")
+	m.schedules = loadRoutineSchedules("routines")
+}
+
+// openAgenda switches to the agenda starting from the selected calendar day.
+func (m *model) openAgenda() {
+	m.state = stateAgenda
+	m.updatePaneSizes()
+	m.agendaViewport.SetContent(renderAgendaContent(*m))
+	m.agendaViewport.GotoTop()
+}
+
+// updateCalendar handles keys for the calendar and agenda views.
+func (m *model) updateCalendar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		m.state = stateCountdown
+		m.refreshCountdowns()
+		return m, tea.Batch(m.countdownSpinner.Tick, tick())
+	case "t":
+		m.calendarDay = startOfDay(time.Now())
+	}
+
+	if m.state == stateAgenda {
+		switch msg.String() {
+		case "c":
+			m.state = stateCalendar
+			return m, nil
+		case "t":
+			m.openAgenda()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.agendaViewport, cmd = m.agendaViewport.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "left", "h":
+		m.calendarDay = m.calendarDay.AddDate(0, 0, -1)
+	case "right", "l":
+		m.calendarDay = m.calendarDay.AddDate(0, 0, 1)
+	case "up", "k":
+		m.calendarDay = m.calendarDay.AddDate(0, 0, -7)
+	case "down", "j":
+		m.calendarDay = m.calendarDay.AddDate(0, 0, 7)
+	case "pgup", "[":
+		m.calendarDay = m.calendarDay.AddDate(0, -1, 0)
+	case "pgdown", "]":
+		m.calendarDay = m.calendarDay.AddDate(0, 1, 0)
+	case "enter", "g":
+		m.openAgenda()
+	}
+	return m, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartOfWeek(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		now       time.Time
+		weekStart time.Weekday
+		want      time.Time
+	}{
+		{sunday, time.Monday, time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)},
+		{sunday, time.Sunday, time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)},
+		{sunday.AddDate(0, 0, 1), time.Monday, time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)},
+		{sunday.AddDate(0, 0, -1), time.Saturday, time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		if got := startOfWeek(tt.now, tt.weekStart); !got.Equal(tt.want) {
+			t.Errorf("startOfWeek(%s, %s) = %s, want %s", tt.now.Format("Mon 2 Jan"), tt.weekStart, got, tt.want)
+		}
+	}
+}
+
+func TestEventGroupWeekStart(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	tuesday := sunday.AddDate(0, 0, 2)
+	if got := eventGroup(tuesday, sunday, time.Monday); got != "Later" {
+		t.Errorf("with a Monday start, Tuesday after Sunday is in %q, want Later", got)
+	}
+	if got := eventGroup(tuesday, sunday, time.Sunday); got != "This week" {
+		t.Errorf("with a Sunday start, Tuesday after Sunday is in %q, want This week", got)
+	}
+	if got := eventGroup(sunday.Add(time.Hour), sunday, time.Monday); got != "Today" {
+		t.Errorf("later today is in %q, want Today", got)
+	}
+	if got := eventGroup(sunday.Add(-time.Hour), sunday, time.Monday); got != "Past" {
+		t.Errorf("earlier today is in %q, want Past", got)
+	}
+}
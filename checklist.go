@@ -0,0 +1,103 @@
+package main
+
+// Checklists are stored flat, in file order, with a Depth per item. An item's
+// children are the items that directly follow it with a greater depth.
+
+// parentOf returns the index of the item's parent, or -1 for top-level items.
+func parentOf(list []ChecklistItem, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if list[j].Depth < list[i].Depth {
+			return j
+		}
+	}
+	return -1
+}
+
+// hasChildren reports whether the item has nested sub-items.
+func hasChildren(list []ChecklistItem, i int) bool {
+	return i+1 < len(list) && list[i+1].Depth > list[i].Depth
+}
+
+// subtreeEnd returns the index just past the item's last descendant.
+func subtreeEnd(list []ChecklistItem, i int) int {
+	j := i + 1
+	for j < len(list) && list[j].Depth > list[i].Depth {
+		j++
+	}
+	return j
+}
+
+// isVisible reports whether none of the item's ancestors are folded.
+func isVisible(list []ChecklistItem, i int) bool {
+	for p := parentOf(list, i); p >= 0; p = parentOf(list, p) {
+		if list[p].Collapsed {
+			return false
+		}
+	}
+	return true
+}
+
+// nextVisible returns the next visible item after i, or i if there is none.
+func nextVisible(list []ChecklistItem, i int) int {
+	for j := i + 1; j < len(list); j++ {
+		if isVisible(list, j) {
+			return j
+		}
+	}
+	return i
+}
+
+// prevVisible returns the previous visible item before i, or i if there is none.
+func prevVisible(list []ChecklistItem, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if isVisible(list, j) {
+			return j
+		}
+	}
+	return i
+}
+
+// toggleItem flips an item and sets all of its sub-items to the same state.
+// A parent becomes complete once all of its sub-items are.
+func toggleItem(list []ChecklistItem, i int) {
+	complete := !list[i].Complete
+	for j := i; j < subtreeEnd(list, i); j++ {
+		list[j].Complete = complete
+	}
+	for p := parentOf(list, i); p >= 0; p = parentOf(list, p) {
+		all := true
+		for j := p + 1; j < subtreeEnd(list, p); j++ {
+			if !list[j].Complete {
+				all = false
+				break
+			}
+		}
+		list[p].Complete = all
+	}
+}
+
+// foldItem collapses an item with children, or moves the selection to its parent.
+// It returns the new selected index.
+func foldItem(list []ChecklistItem, i int) int {
+	if hasChildren(list, i) && !list[i].Collapsed {
+		list[i].Collapsed = true
+		return i
+	}
+	if p := parentOf(list, i); p >= 0 {
+		return p
+	}
+	return i
+}
+
+// unfoldItem expands a folded item, or moves the selection to its first child.
+// It returns the new selected index.
+func unfoldItem(list []ChecklistItem, i int) int {
+	if !hasChildren(list, i) {
+		return i
+	}
+	if list[i].Collapsed {
+		list[i].Collapsed = false
+		return i
+	}
+	return i + 1
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// testChecklist builds a checklist from lines indented two spaces per level:
+//
+//	Pack
+//	  Clothes
+//	    Socks
+//	    Shirts
+//	  Books
+//	Leave
+func testChecklist() []ChecklistItem {
+	lines := []string{"Pack", "  Clothes", "    Socks", "    Shirts", "  Books", "Leave"}
+	var list []ChecklistItem
+	for _, line := range lines {
+		text := strings.TrimLeft(line, " ")
+		list = append(list, ChecklistItem{Text: text, Depth: (len(line) - len(text)) / 2})
+	}
+	return list
+}
+
+// completed lists the texts of the complete items.
+func completed(list []ChecklistItem) string {
+	var done []string
+	for _, item := range list {
+		if item.Complete {
+			done = append(done, item.Text)
+		}
+	}
+	return strings.Join(done, ",")
+}
+
+func TestParentOf(t *testing.T) {
+	list := testChecklist()
+	for i, want := range []int{-1, 0, 1, 1, 0, -1} {
+		if got := parentOf(list, i); got != want {
+			t.Errorf("parentOf(%s) = %d, want %d", list[i].Text, got, want)
+		}
+	}
+}
+
+func TestToggleItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		toggles []int
+		want    string
+	}{
+		{"a parent ticks its children", []int{1}, "Clothes,Socks,Shirts"},
+		{"the last child completes its parents", []int{2, 3, 4}, "Pack,Clothes,Socks,Shirts,Books"},
+		{"a parent waits for all children", []int{2}, "Socks"},
+		{"unticking a child reopens its parents", []int{0, 3}, "Socks,Books"},
+		{"unticking a parent unticks its children", []int{0, 0}, ""},
+		{"top-level items stand alone", []int{5}, "Leave"},
+	}
+	for _, tt := range tests {
+		list := testChecklist()
+		for _, i := range tt.toggles {
+			toggleItem(list, i)
+		}
+		if got := completed(list); got != tt.want {
+			t.Errorf("%s: complete %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFoldAndVisibility(t *testing.T) {
+	list := testChecklist()
+
+	// Folding Clothes hides Socks and Shirts from navigation.
+	if got := foldItem(list, 1); got != 1 || !list[1].Collapsed {
+		t.Fatalf("foldItem(Clothes) = %d, collapsed %v", got, list[1].Collapsed)
+	}
+	for i, want := range []bool{true, true, false, false, true, true} {
+		if got := isVisible(list, i); got != want {
+			t.Errorf("isVisible(%s) = %v, want %v", list[i].Text, got, want)
+		}
+	}
+	if got := nextVisible(list, 1); got != 4 {
+		t.Errorf("nextVisible(Clothes) = %d, want Books (4)", got)
+	}
+	if got := prevVisible(list, 4); got != 1 {
+		t.Errorf("prevVisible(Books) = %d, want Clothes (1)", got)
+	}
+
+	tests := []struct {
+		name string
+		fn   func([]ChecklistItem, int) int
+		i    int
+		want int
+	}{
+		{"folding a folded item selects its parent", foldItem, 1, 0},
+		{"folding a leaf selects its parent", foldItem, 4, 0},
+		{"folding a top-level leaf stays", foldItem, 5, 5},
+		{"unfolding a folded item opens it", unfoldItem, 1, 1},
+		{"unfolding an open item selects its first child", unfoldItem, 1, 2},
+		{"unfolding a leaf stays", unfoldItem, 2, 2},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(list, tt.i); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if list[1].Collapsed {
+		t.Error("Clothes still folded after unfolding it")
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configPath is where user settings are read from.
+const configPath = "config/config.md"
+
+// Config holds user settings read from config/config.md.
+type Config struct {
+	MaxEvents int    // Upcoming events listed on the countdown screen before "+N more", 0 lists all
+	Notify    bool   // Send a desktop notification when a scheduled routine is due
+	API       string // Address of the local HTTP API, e.g. "127.0.0.1:7878" or "unix:/tmp/timey.sock", empty turns it off
+
+	QuoteMode      string        // "daily" keeps one quote all day, "rotate" changes it every QuoteInterval
+	QuoteInterval  time.Duration // How often quotes rotate
+	FavoriteQuotes bool          // Only pick quotes tagged #favorite, when there are any
+
+	Countdowns []string      // Countdown targets: "end of day", "workday", "bedtime", "next event" or "14:30 label"
+	HasWorkday bool          // Whether Workday is set
+	WorkStart  time.Duration // Working window as offsets from midnight
+	WorkEnd    time.Duration
+	HasBedtime bool
+	Bedtime    time.Duration // Offset from midnight
+
+	WeekStart       time.Weekday // First day of the week for the week bar, goals, the calendar and the heatmap
+	Bars            []string     // Progress bars in the greeting: "year", "quarter", "fiscal year", "month", "week", "day"
+	FiscalYearStart time.Month   // First month of the fiscal year, which quarters count from
+	CustomBars      []customBar  // Bars between two dates, one per Bar line
+}
+
+// customBar is a user-defined progress bar such as "Project deadline 2026-09-01 to 2026-12-15".
+type customBar struct {
+	Label    string
+	From, To time.Time // First and last day
+}
+
+// customBarRE matches "label 2006-01-02 to 2006-01-02".
+var customBarRE = regexp.MustCompile(`^(.+?)\s+(\d{4}-\d{2}-\d{2})\s*(?:to|-|–)\s*(\d{4}-\d{2}-\d{2})$`)
+
+// barNames are the built-in progress bars accepted by the Bars setting.
+var barNames = []string{"year", "quarter", "fiscal year", "month", "week", "day"}
+
+// defaultConfig returns the settings used when config.md is missing or a key is not set.
+func defaultConfig() Config {
+	return Config{
+		MaxEvents:     5,
+		QuoteMode:     "daily",
+		QuoteInterval: 5 * time.Minute,
+		Countdowns:    []string{"end of day"},
+
+		WeekStart:       time.Monday,
+		Bars:            []string{"year", "month", "week", "day"},
+		FiscalYearStart: time.January,
+	}
+}
+
+// loadConfig reads `- Key: value` lines from a markdown file on top of the defaults.
+// Unknown keys are ignored so the file can carry notes.
+func loadConfig(path string) (Config, error) {
+	cfg := defaultConfig()
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	defer file.Close()
+
+	settingRE := regexp.MustCompile(`^-\s*([^:]+):\s*(.*)$`)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		match := settingRE.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(match[1]))
+		value := strings.TrimSpace(match[2])
+
+		switch key {
+		case "max events":
+			if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+				cfg.MaxEvents = n
+			}
+		case "notify":
+			cfg.Notify = parseBool(value)
+		case "quote mode":
+			if v := strings.ToLower(value); v == "daily" || v == "rotate" {
+				cfg.QuoteMode = v
+			}
+		case "quote interval":
+			if d, err := parseDuration(value); err == nil && d > 0 {
+				cfg.QuoteInterval = d
+			}
+		case "favorites only":
+			cfg.FavoriteQuotes = parseBool(value)
+		case "countdowns", "countdown":
+			var targets []string
+			for _, t := range strings.Split(value, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					targets = append(targets, t)
+				}
+			}
+			if len(targets) > 0 {
+				cfg.Countdowns = targets
+			}
+		case "workday", "working hours":
+			if start, end, ok := parseClockRange(value); ok {
+				cfg.HasWorkday, cfg.WorkStart, cfg.WorkEnd = true, start, end
+			}
+		case "bedtime":
+			if t, ok := parseClock(value); ok {
+				cfg.HasBedtime, cfg.Bedtime = true, t
+			}
+		case "week start", "first day of week":
+			if d, ok := parseWeekday(value); ok {
+				cfg.WeekStart = d
+			}
+		case "bars":
+			var bars []string
+			for _, b := range strings.Split(value, ",") {
+				b = strings.Join(strings.Fields(strings.ToLower(b)), " ")
+				if slices.Contains(barNames, b) {
+					bars = append(bars, b)
+				}
+			}
+			if len(bars) > 0 || strings.EqualFold(value, "none") {
+				cfg.Bars = bars
+			}
+		case "fiscal year start":
+			if month, ok := parseMonth(value); ok {
+				cfg.FiscalYearStart = month
+			}
+		case "bar":
+			if bar, ok := parseCustomBar(value); ok {
+				cfg.CustomBars = append(cfg.CustomBars, bar)
+			}
+		case "api":
+			if strings.ToLower(value) != "off" {
+				cfg.API = value
+			}
+		}
+	}
+	return cfg, scanner.Err()
+}
+
+// parseClock reads a time of day like "9", "9:30" or "24:00" as an offset from midnight.
+func parseClock(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" || s == "24" {
+		return 24 * time.Hour, true
+	}
+	for _, layout := range []string{"15:04", "15"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+		}
+	}
+	return 0, false
+}
+
+// parseClockRange reads a window like "9:00-17:30".
+func parseClockRange(s string) (time.Duration, time.Duration, bool) {
+	from, to, ok := strings.Cut(strings.ReplaceAll(s, "–", "-"), "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, ok1 := parseClock(from)
+	end, ok2 := parseClock(to)
+	if !ok1 || !ok2 || end <= start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// parseWeekday reads a day name such as "Sunday" or "mon".
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 3 {
+		return 0, false
+	}
+	d, ok := weekdayNames[s[:3]]
+	return d, ok
+}
+
+// parseMonth reads a month name such as "April" or "apr", or its number.
+func parseMonth(s string) (time.Month, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil {
+		return time.Month(n), n >= 1 && n <= 12
+	}
+	for m := time.January; m <= time.December; m++ {
+		if len(s) >= 3 && strings.HasPrefix(strings.ToLower(m.String()), s) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// parseCustomBar reads "label 2006-01-02 to 2006-01-02". The last day must not be before the first.
+func parseCustomBar(s string) (customBar, bool) {
+	match := customBarRE.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return customBar{}, false
+	}
+	from, err1 := time.ParseInLocation("2006-01-02", match[2], time.Local)
+	to, err2 := time.ParseInLocation("2006-01-02", match[3], time.Local)
+	if err1 != nil || err2 != nil || to.Before(from) {
+		return customBar{}, false
+	}
+	return customBar{Label: strings.TrimSpace(match[1]), From: from, To: to}, true
+}
+
+// parseBool accepts the usual spellings of yes and no in settings.
+func parseBool(s string) bool {
+	switch strings.ToLower(s) {
+	case "yes", "true", "on", "1":
+		return true
+	}
+	return false
+}
@@ -4,6 +4,7 @@ package main
 import (
     "fmt"
     "math"
+    "regexp"
     "strings"
     "time"
 
@@ -18,14 +19,15 @@ func tick() tea.Cmd {
 	})
 }
 
-// greet generates a greeting based on the current hour
-func greet(now time.Time) string {
+// greet generates a greeting based on the current hour, followed by the
+// progress bars listed in the Bars setting.
+func greet(now time.Time, cfg Config) string {
 	year := now.Year()
 	month := int(now.Month())
 	mday := now.Day()
 	hour, minute := now.Hour(), now.Minute()
-	weekday := int(now.Weekday())
 	yday := now.YearDay()
+	_, isoWeek := now.ISOWeek()
 
 	greeting := ""
 	switch {
@@ -36,15 +38,41 @@ func greet(now time.Time) string {
 	default:
 		greeting += "Good evening!\n"
 	}
-	greeting += fmt.Sprintf("Today is %s, %d %s %d.\n", now.Weekday(), mday, now.Month(), year)
-	greeting += "\n year progress: "
-	greeting += yearLeft(year, yday) + "\n"
-	greeting += "\n month progress: "
-	greeting += monthLeft(mday, month, year) + "\n"
-	greeting += "\n week progress: "
-	greeting += weekLeft(weekday) + "\n"
-	greeting += "\n day progress: "
-	greeting += dayLeft(hour, minute) + "\n"
+	greeting += fmt.Sprintf("Today is %s, %d %s %d, week %d.\n", now.Weekday(), mday, now.Month(), year, isoWeek)
+
+	for _, bar := range cfg.Bars {
+		switch bar {
+		case "year":
+			greeting += "\n year progress: "
+			greeting += yearLeft(year, yday) + "\n"
+		case "quarter":
+			from, to, q := fiscalQuarter(now, cfg.FiscalYearStart)
+			greeting += fmt.Sprintf("\n Q%d progress: ", q)
+			greeting += periodLeft(now, from, to) + "\n"
+		case "fiscal year":
+			from, to := fiscalYear(now, cfg.FiscalYearStart)
+			greeting += fmt.Sprintf("\n FY%d progress: ", to.AddDate(0, 0, -1).Year())
+			greeting += periodLeft(now, from, to) + "\n"
+		case "month":
+			greeting += "\n month progress: "
+			greeting += monthLeft(mday, month, year) + "\n"
+		case "week":
+			greeting += "\n week progress: "
+			greeting += weekLeft(now.Weekday(), cfg.WeekStart) + "\n"
+		case "day":
+			if cfg.HasWorkday {
+				greeting += "\n workday progress: "
+				greeting += workdayLeft(now, cfg.WorkStart, cfg.WorkEnd) + "\n"
+			} else {
+				greeting += "\n day progress: "
+				greeting += dayLeft(hour, minute) + "\n"
+			}
+		}
+	}
+	for _, bar := range cfg.CustomBars {
+		greeting += fmt.Sprintf("\n %s: ", bar.Label)
+		greeting += periodLeft(now, bar.From, bar.To.AddDate(0, 0, 1)) + "\n"
+	}
 
 	return greeting
 }
@@ -102,10 +130,39 @@ func monthLeft(mday, month, year int) string {
 	return progressBar(float64(mday), float64(lastDay), left, 30, false)
 }
 
-// weekLeft returns the progress bar for the current week
-func weekLeft(weekday int) string {
-	left := float64(7 - weekday)
-	return progressBar(float64(weekday), 7, left, 30, false)
+// weekLeft returns the progress bar for the current week. Like the month and
+// year bars it counts today as passed, so the first day shows 1/7.
+func weekLeft(weekday, weekStart time.Weekday) string {
+	day := dayOfWeek(weekday, weekStart)
+	left := float64(7 - day)
+	return progressBar(float64(day), 7, left, 30, false)
+}
+
+// periodLeft returns the progress bar for the days from `from` up to, not including, `to`.
+func periodLeft(now, from, to time.Time) string {
+	total := math.Round(to.Sub(from).Hours() / 24)
+	day := math.Floor(startOfDay(now).Sub(from).Hours()/24) + 1
+	day = math.Min(math.Max(day, 0), total)
+	return progressBar(day, total, total-day, 30, false)
+}
+
+// fiscalYear returns the fiscal year containing now, for a year starting in startMonth.
+func fiscalYear(now time.Time, startMonth time.Month) (time.Time, time.Time) {
+	year := now.Year()
+	if now.Month() < startMonth {
+		year--
+	}
+	from := time.Date(year, startMonth, 1, 0, 0, 0, 0, now.Location())
+	return from, from.AddDate(1, 0, 0)
+}
+
+// fiscalQuarter returns the quarter containing now, counted from the start of the fiscal year.
+func fiscalQuarter(now time.Time, startMonth time.Month) (time.Time, time.Time, int) {
+	yearStart, _ := fiscalYear(now, startMonth)
+	months := (now.Year()-yearStart.Year())*12 + int(now.Month()) - int(yearStart.Month())
+	q := months / 3
+	from := yearStart.AddDate(0, 3*q, 0)
+	return from, from.AddDate(0, 3, 0), q + 1
 }
 
 // dayLeft returns the progress bar for the current day
@@ -117,9 +174,116 @@ func dayLeft(hour, minute int) string {
 	return progressBar(totalHours, 24.0, left, 30, true)
 }
 
+// workdayLeft returns the progress bar for the working window, given as
+// offsets from midnight. Before the window it is empty, after it full.
+func workdayLeft(now time.Time, start, end time.Duration) string {
+	sinceMidnight := now.Sub(startOfDay(now))
+	done := math.Min(math.Max((sinceMidnight-start).Hours(), 0), (end - start).Hours())
+	left := math.Floor((end - start).Hours() - done)
+	return progressBar(done, (end - start).Hours(), left, 30, true)
+}
+
 // timeLeftToday returns time left until end of day
-func timeLeftToday() time.Duration {
-	now := time.Now()
+func timeLeftToday(now time.Time) time.Duration {
 	eod := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
 	return eod.Sub(now)
 }
+
+
+// countdown is one of the countdowns on the countdown screen.
+type countdown struct {
+	Label     string
+	Remaining time.Duration
+}
+
+// countdownTargetRE matches a custom target like "14:30 standup".
+var countdownTargetRE = regexp.MustCompile(`^(\d{1,2}:\d{2})\s*(.*)$`)
+
+// countdownsAt works out the countdowns listed in the Countdowns setting.
+// Targets that have passed today stay at zero.
+func countdownsAt(cfg Config, events []Event, now time.Time) []countdown {
+	var list []countdown
+	today := startOfDay(now)
+	until := func(t time.Time) time.Duration {
+		return max(t.Sub(now), 0)
+	}
+
+	for _, target := range cfg.Countdowns {
+		switch strings.ToLower(target) {
+		case "end of day", "day":
+			list = append(list, countdown{"time left today", timeLeftToday(now)})
+		case "workday", "end of workday", "work":
+			if cfg.HasWorkday {
+				list = append(list, countdown{"time left at work", until(today.Add(cfg.WorkEnd))})
+			}
+		case "bedtime":
+			if cfg.HasBedtime {
+				list = append(list, countdown{"time to bed", until(bedtimeAt(now, cfg.Bedtime))})
+			}
+		case "next event", "event":
+			for _, u := range upcomingEvents(events, now) {
+				if u.Next.After(now) {
+					list = append(list, countdown{"until " + eventDisplayName(u.Event), u.Next.Sub(now)})
+					break
+				}
+			}
+		default:
+			match := countdownTargetRE.FindStringSubmatch(target)
+			if match == nil {
+				continue
+			}
+			t, ok := atTime(today, match[1])
+			if !ok {
+				continue
+			}
+			label := strings.TrimSpace(match[2])
+			if label == "" {
+				label = "until " + match[1]
+			}
+			list = append(list, countdown{label, until(t)})
+		}
+	}
+	if len(list) == 0 {
+		list = append(list, countdown{"time left today", timeLeftToday(now)})
+	}
+	return list
+}
+
+// nightEnds is when a night is over for the bedtime countdown. Until then a
+// bedtime that has passed stays at zero instead of moving on to the next night.
+const nightEnds = 6 * time.Hour
+
+// bedtimeAt returns tonight's bedtime. A bedtime after midnight, such as 00:30,
+// falls on the next calendar day when it is still evening.
+func bedtimeAt(now time.Time, bedtime time.Duration) time.Time {
+	day := startOfDay(now)
+	if now.Sub(day) < nightEnds {
+		day = day.AddDate(0, 0, -1) // Still last night
+	}
+	if bedtime < nightEnds {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.Add(bedtime)
+}
+
+// formatCountdown shows a countdown as HH:MM:SS, with days in front when it is further away.
+func formatCountdown(d time.Duration) string {
+	h := int(d.Hours())
+	mn := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+	if h >= 24 {
+		return fmt.Sprintf("%dd %02d:%02d:%02d", h/24, h%24, mn, s)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", h, mn, s)
+}
+
+// refreshCountdowns updates the countdowns and the greeting bars for the current time.
+func (m *model) refreshCountdowns() {
+	now := time.Now()
+	previous := len(m.countdowns)
+	m.countdowns = countdownsAt(m.config, m.events, now)
+	m.countdownGreetText = greet(now, m.config)
+	if len(m.countdowns) != previous {
+		m.updatePaneSizes() // The event list starts lower with more countdowns
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeekLeft(t *testing.T) {
+	tests := []struct {
+		weekday, weekStart time.Weekday
+		want               string
+	}{
+		{time.Monday, time.Monday, "6 days left"},
+		{time.Sunday, time.Monday, "0 days left"},
+		{time.Sunday, time.Sunday, "6 days left"},
+		{time.Saturday, time.Sunday, "0 days left"},
+	}
+	for _, tt := range tests {
+		if got := weekLeft(tt.weekday, tt.weekStart); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("weekLeft(%s, %s) = %q, want %q", tt.weekday, tt.weekStart, got, tt.want)
+		}
+	}
+}
+
+func TestFiscalQuarter(t *testing.T) {
+	now := time.Date(2026, 2, 10, 12, 0, 0, 0, time.Local)
+	from, to, q := fiscalQuarter(now, time.April)
+	if q != 4 || from.Month() != time.January || to.Month() != time.April || from.Year() != 2026 {
+		t.Errorf("fiscalQuarter = %s to %s, Q%d; want Q4 from January to April 2026", from, to, q)
+	}
+	fyFrom, fyTo := fiscalYear(now, time.April)
+	if fyFrom.Year() != 2025 || fyTo.Year() != 2026 || fyFrom.Month() != time.April {
+		t.Errorf("fiscalYear = %s to %s, want April 2025 to April 2026", fyFrom, fyTo)
+	}
+}
+
+func TestPeriodLeftClamps(t *testing.T) {
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 9, 11, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{from.AddDate(0, 0, -5), "10 days left"},
+		{from, "9 days left"},
+		{to.AddDate(0, 0, 3), "0 days left"},
+	}
+	for _, tt := range tests {
+		if got := periodLeft(tt.now, from, to); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("periodLeft(%s) = %q, want %q", tt.now.Format("2 Jan"), got, tt.want)
+		}
+	}
+}
+
+func TestCountdownsAt(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	cfg := defaultConfig()
+	cfg.HasWorkday, cfg.WorkStart, cfg.WorkEnd = true, 9*time.Hour, 17*time.Hour
+	cfg.HasBedtime, cfg.Bedtime = true, 30*time.Minute
+	cfg.Countdowns = []string{"end of day", "workday", "bedtime", "next event", "14:30 standup"}
+	events := []Event{{Name: "Dinner", DateTime: day.Add(19 * time.Hour)}}
+
+	tests := []struct {
+		now  time.Time
+		want []countdown
+	}{
+		{day.Add(12 * time.Hour), []countdown{
+			{"time left today", 11*time.Hour + 59*time.Minute + 59*time.Second},
+			{"time left at work", 5 * time.Hour},
+			{"time to bed", 12*time.Hour + 30*time.Minute},
+			{"until Dinner", 7 * time.Hour},
+			{"standup", 2*time.Hour + 30*time.Minute},
+		}},
+		// Past midnight the 00:30 bedtime is tonight's, not tomorrow night's.
+		{day.Add(24*time.Hour + 10*time.Minute), []countdown{
+			{"time left today", 23*time.Hour + 49*time.Minute + 59*time.Second},
+			{"time left at work", 16*time.Hour + 50*time.Minute},
+			{"time to bed", 20 * time.Minute},
+			{"standup", 14*time.Hour + 20*time.Minute},
+		}},
+		// After the bedtime it stays at zero until the night is over.
+		{day.Add(26 * time.Hour), []countdown{
+			{"time left today", 21*time.Hour + 59*time.Minute + 59*time.Second},
+			{"time left at work", 15 * time.Hour},
+			{"time to bed", 0},
+			{"standup", 12*time.Hour + 30*time.Minute},
+		}},
+	}
+	for _, tt := range tests {
+		got := countdownsAt(cfg, events, tt.now)
+		if len(got) != len(tt.want) {
+			t.Errorf("at %s: %+v, want %+v", tt.now.Format("Jan 2 15:04"), got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("at %s: countdown %d = %+v, want %+v", tt.now.Format("Jan 2 15:04"), i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestCountdownsAtDefaultsToEndOfDay(t *testing.T) {
+	now := time.Date(2026, 10, 19, 23, 0, 0, 0, time.Local)
+	cfg := defaultConfig()
+	cfg.Countdowns = []string{"workday"} // No workday set, so nothing to count down to
+	got := countdownsAt(cfg, nil, now)
+	want := countdown{"time left today", 59*time.Minute + 59*time.Second}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("countdownsAt = %+v, want %+v", got, []countdown{want})
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The session engine keeps time per habit. Each habit carries its cumulative
+// run and pause time across visits, so going back to a habit resumes its
+// progress. Every visit is recorded in m.sessions as its own segment.
+//
+// While a habit is open:
+//   - m.visitStart is when the visit began,
+//   - m.elapsed is the run time of the visit up to m.startTime,
+//   - m.visitPaused is the time the visit spent in statePaused.
+//
+// statePaused pauses the open habit, statePausing is a break between habits
+// with no habit open.
+
+// habitElapsed returns the total run time of the current habit over all visits,
+// including the stretch that is running now.
+func (m model) habitElapsed() time.Duration {
+	if m.current >= len(m.routines) {
+		return 0
+	}
+	total := m.routines[m.current].Elapsed + m.elapsed
+	if m.state == stateRunning {
+		total += time.Since(m.startTime)
+	}
+	return total
+}
+
+// startHabit opens a visit on the current habit, or continues the open one, and starts the clock.
+// It does nothing when no habit of the routine runs today.
+func (m *model) startHabit() tea.Cmd {
+	if len(m.routines) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if !m.sessionStarted {
+		m.sessionStarted = true
+		m.runHooks(hookSessionStart)
+	}
+	if m.state == statePausing {
+		m.recordPause(m.pauseStart, now)
+		m.runHooks(hookResume)
+	}
+	opened := m.visitStart.IsZero()
+	if opened {
+		m.visitStart = now
+	}
+	m.state = stateRunning
+	m.startTime = now
+	m.itemStart = now
+	if opened {
+		m.runHooks(hookHabitStart)
+	}
+	return tick()
+}
+
+// pauseHabit stops the clock on the open habit without closing the visit.
+func (m *model) pauseHabit() {
+	if m.state != stateRunning {
+		return
+	}
+	m.flushItemTime()
+	m.elapsed += time.Since(m.startTime)
+	m.pauseStart = time.Now()
+	m.state = statePaused
+	m.runHooks(hookPause)
+}
+
+// resumeHabit restarts the clock after a pause or a break between habits.
+func (m *model) resumeHabit() tea.Cmd {
+	if m.state == statePaused {
+		m.visitPaused += m.recordPause(m.pauseStart, time.Now())
+		m.runHooks(hookResume)
+		// The tick loop kept running through the pause, so don't start another one.
+		m.startHabit()
+		return nil
+	}
+	return m.startHabit()
+}
+
+// closeVisit ends the open visit on the current habit and records it as a segment.
+func (m *model) closeVisit(skipped bool) {
+	now := time.Now()
+	switch m.state {
+	case stateRunning:
+		m.flushItemTime()
+		m.elapsed += now.Sub(m.startTime)
+	case statePaused:
+		m.visitPaused += m.recordPause(m.pauseStart, now)
+	}
+
+	recorded := m.current < len(m.routines) && (!m.visitStart.IsZero() || skipped)
+	if recorded {
+		start := m.visitStart
+		if start.IsZero() {
+			start = now
+		}
+		r := &m.routines[m.current]
+		r.Elapsed += m.elapsed
+		r.Paused += m.visitPaused
+		if skipped {
+			r.Status = habitSkipped
+		}
+		m.sessions = append(m.sessions, Session{
+			RoutineTitle: r.Title,
+			Start:        start,
+			End:          now,
+			Elapsed:      m.elapsed,
+			Paused:       m.visitPaused,
+			Skipped:      skipped,
+		})
+	}
+
+	m.visitStart = time.Time{}
+	m.elapsed = 0
+	m.visitPaused = 0
+	m.selectedTodo = 0
+	if m.state == stateRunning || m.state == statePaused {
+		m.state = stateReadyToStart
+	}
+	if recorded {
+		m.runHooks(hookHabitEnd)
+	}
+}
+
+// goToHabit closes the current visit and waits to start habit i.
+func (m *model) goToHabit(i int) {
+	m.closeVisit(false)
+	m.current = i
+	if m.current < len(m.routines) && m.routines[m.current].Status != habitDone {
+		m.routines[m.current].Status = habitPending
+	}
+	m.state = stateReadyToStart
+}
+
+// timeUp reports whether the current habit should finish on its own. Only the first
+// completion does: a done habit that was gone back to runs until it is finished by hand.
+func (m model) timeUp() bool {
+	if m.current >= len(m.routines) || m.routines[m.current].Status == habitDone {
+		return false
+	}
+	return m.habitElapsed() >= m.currentDuration()
+}
+
+// finishHabit marks the current habit done and moves on, stopping the session after the last one.
+func (m *model) finishHabit() {
+	if m.current < len(m.routines) {
+		m.routines[m.current].Status = habitDone
+	}
+	m.closeVisit(false)
+	m.moveToNextPending()
+}
+
+// skipHabit records the current habit as skipped and moves on to the next one.
+func (m *model) skipHabit() {
+	m.closeVisit(true)
+	m.moveToNextPending()
+}
+
+// moveToNextPending waits to start the next habit that is neither done nor
+// skipped, stopping the session when there is none left.
+func (m *model) moveToNextPending() {
+	if m.current = m.nextPendingHabit(m.current); m.current < 0 {
+		m.current = len(m.routines)
+		*m = m.stopSession()
+		return
+	}
+	m.state = stateReadyToStart
+}
+
+// recordPause adds a pause, either inside a habit or a break between habits,
+// to the session total and the timeline. It returns the pause length.
+func (m *model) recordPause(start, end time.Time) time.Duration {
+	m.pauses = append(m.pauses, pauseSpan{Start: start, End: end})
+	m.totalPaused += end.Sub(start)
+	return end.Sub(start)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testSession(statuses ...habitStatus) model {
+	m := model{state: stateReadyToStart}
+	for i, s := range statuses {
+		m.routines = append(m.routines, Routine{Title: string(rune('A' + i)), Time: "1m", Status: s})
+	}
+	return m
+}
+
+func TestFinishMovesToNextPendingHabit(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []habitStatus
+		current  int
+		want     int
+	}{
+		{"next in line", []habitStatus{habitPending, habitPending, habitPending}, 0, 1},
+		{"over a skipped habit", []habitStatus{habitPending, habitSkipped, habitPending}, 0, 2},
+		{"over a done habit", []habitStatus{habitPending, habitDone, habitPending}, 0, 2},
+		{"back to an earlier one", []habitStatus{habitPending, habitDone, habitPending}, 2, 0},
+	}
+	for _, tt := range tests {
+		m := testSession(tt.statuses...)
+		m.current = tt.current
+		m.startHabit()
+		m.finishHabit()
+		if m.current != tt.want || m.state != stateReadyToStart {
+			t.Errorf("%s: current %d in state %d, want %d ready to start", tt.name, m.current, m.state, tt.want)
+		}
+		if m.routines[tt.current].Status != habitDone {
+			t.Errorf("%s: finished habit has status %v", tt.name, m.routines[tt.current].Status)
+		}
+	}
+}
+
+func TestSkipMovesToNextPendingHabit(t *testing.T) {
+	m := testSession(habitPending, habitSkipped, habitPending)
+	m.startHabit()
+	m.skipHabit()
+	if m.current != 2 || m.routines[0].Status != habitSkipped {
+		t.Errorf("current %d, first habit %v; want 2 and skipped", m.current, m.routines[0].Status)
+	}
+}
+
+func TestGoingBackToDoneHabitDoesNotFinishIt(t *testing.T) {
+	m := testSession(habitPending, habitPending)
+	m.routines[0].Elapsed = 2 * time.Minute // Ran over its 1m before being finished
+	m.routines[0].Status = habitDone
+	m.current = 1
+	m.startHabit()
+
+	m.goToHabit(0)
+	m.startHabit()
+	if m.timeUp() {
+		t.Error("a done habit that was gone back to finished on the next tick")
+	}
+
+	m.goToHabit(1)
+	m.routines[1].Elapsed = time.Minute
+	m.startHabit()
+	if !m.timeUp() {
+		t.Error("a pending habit past its duration did not finish")
+	}
+}
+
+func TestStartHabitWithoutHabitsToday(t *testing.T) {
+	m := model{state: stateRoutineView}
+	if cmd := m.startHabit(); cmd != nil || m.state != stateRoutineView || m.sessionStarted {
+		t.Errorf("started a session with no habits: state %d, started %v", m.state, m.sessionStarted)
+	}
+	if _, err := m.apiCommand(apiCommandMsg{command: "resume"}); err == nil {
+		t.Error("the API started a routine with no habits today")
+	}
+}
+
+func TestOverBudgetItemAdvancesOnlyWhenBudgetRunsOut(t *testing.T) {
+	m := testSession(habitPending)
+	m.routines[0].Checklist = []ChecklistItem{
+		{Text: "Stretch", Budget: time.Minute, Spent: 2 * time.Minute},
+		{Text: "Plank", Budget: time.Minute, Spent: time.Minute - time.Millisecond},
+		{Text: "Tea"},
+	}
+	m.startHabit()
+	m.selectedTodo = 1
+
+	// Going back to an item that is already over budget keeps it selected.
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = *next.(*model)
+	m.advanceOverBudgetItem()
+	m.advanceOverBudgetItem()
+	if m.selectedTodo != 0 {
+		t.Fatalf("selected %d after ticks on an over-budget item, want 0", m.selectedTodo)
+	}
+
+	// An item whose budget runs out while selected moves the selection on.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = *next.(*model)
+	m.advanceOverBudgetItem()
+	time.Sleep(2 * time.Millisecond)
+	m.advanceOverBudgetItem()
+	if m.selectedTodo != 2 {
+		t.Errorf("selected %d after the budget ran out, want 2", m.selectedTodo)
+	}
+}
@@ -7,6 +7,7 @@ import (
     "time"
 	"fmt"
     "regexp"
+    "sort"
 	tea "github.com/charmbracelet/bubbletea"
 
 )
@@ -72,10 +73,16 @@ func loadEvents(path string) ([]Event, error) {
 		line := strings.TrimSpace(scanner.Text())
 
 		if match := eventNameRegex.FindStringSubmatch(line); len(match) > 1 {
-			if currentEvent.Name != "" {
+			if currentEvent.Name != "" || currentEvent.Sealed != "" {
 				events = append(events, currentEvent)
 			}
-			currentEvent = Event{Name: match[1]}
+			name := strings.TrimSpace(match[1])
+			if isSealed(name) {
+				// The real name stays hidden until unlocked in-session.
+				currentEvent = Event{Sealed: name}
+			} else {
+				currentEvent = Event{Name: name}
+			}
 		} else if match := timeRegex.FindStringSubmatch(line); len(match) > 1 {
 			timeStr := strings.TrimSpace(match[1])
 			t, err := parseDate(timeStr)
@@ -91,7 +98,7 @@ func loadEvents(path string) ([]Event, error) {
 			currentEvent.CodePhrase = codePhrase
 		}
 	}
-	if currentEvent.Name != "" {
+	if currentEvent.Name != "" || currentEvent.Sealed != "" {
 		events = append(events, currentEvent)
 	}
 
@@ -104,26 +111,55 @@ func getNextOccurrence(e Event, now time.Time) time.Time {
 		return e.DateTime
 	}
 	for nextTime.Before(now) {
-		switch strings.ToLower(e.Repeat) {
-		case "daily":
-			nextTime = nextTime.AddDate(0, 0, 1)
-		case "weekly":
-			nextTime = nextTime.AddDate(0, 0, 7)
-		case "monthly":
-			// Adding a month can be tricky, so we'll use a library function if possible.
-			// For simplicity here, we'll just add 30 days.
-			nextTime = nextTime.AddDate(0, 1, 0)
-		case "yearly":
-			nextTime = nextTime.AddDate(1, 0, 0)
-		default:
+		next, ok := nextRepeat(nextTime, e.Repeat)
+		if !ok {
 			return e.DateTime
 		}
+		nextTime = next
 	}
 	return nextTime
 }
 
+// nextRepeat advances t by one step of the repeat pattern.
+// It reports false if the pattern is not recognised.
+func nextRepeat(t time.Time, repeat string) (time.Time, bool) {
+	switch strings.ToLower(repeat) {
+	case "daily":
+		return t.AddDate(0, 0, 1), true
+	case "weekly":
+		return t.AddDate(0, 0, 7), true
+	case "monthly":
+		return t.AddDate(0, 1, 0), true
+	case "yearly":
+		return t.AddDate(1, 0, 0), true
+	}
+	return t, false
+}
+
+// occurrencesBetween expands an event into every occurrence in [from, to).
+func occurrencesBetween(e Event, from, to time.Time) []time.Time {
+	var out []time.Time
+	t := getNextOccurrence(e, from)
+	for t.Before(to) {
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+		next, ok := nextRepeat(t, e.Repeat)
+		if e.Repeat == "" || !ok {
+			break
+		}
+		t = next
+	}
+	return out
+}
+
 // saveEventToFile appends a new event to the events.md file.
-func saveEventToFile(event Event) error {
+// Events with a code phrase are sealed with the passphrase before writing.
+func saveEventToFile(event Event, passphrase string) error {
+	if err := sealEvent(&event, passphrase); err != nil {
+		return err
+	}
+
 	f, err := os.OpenFile("events/events.md", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return err
@@ -147,17 +183,106 @@ func saveEventToFile(event Event) error {
 		}
 	}
 
+	_, err = f.WriteString("\n" + formatEvent(lastNumber+1, event))
+	return err
+}
+
+// formatEvent renders a single event in the events.md format.
+// Sealed events are always written as ciphertext, never by their plain name.
+func formatEvent(n int, event Event) string {
+	name := event.Name
+	if event.Sealed != "" {
+		name = event.Sealed
+	}
+
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("\n%d. Event Name: %s\n", lastNumber+1, event.Name))
+	sb.WriteString(fmt.Sprintf("%d. Event Name: %s\n", n, name))
 	sb.WriteString(fmt.Sprintf("- Time: %s\n", event.DateTime.Format("2 January 2006 15:04")))
 	if event.Repeat != "" {
 		sb.WriteString(fmt.Sprintf("- Repeat: %s\n", event.Repeat))
 	}
 	sb.WriteString(fmt.Sprintf("- Code Phrase: %s\n", event.CodePhrase))
+	return sb.String()
+}
 
-	_, err = f.WriteString(sb.String())
-	return err
+// eventNameLineRE splits an event name line into its prefix, the name and trailing space.
+var eventNameLineRE = regexp.MustCompile(`^(\s*\d+\.\s+Event Name:\s+)(.*?)(\s*)$`)
+
+// writeSealedNames replaces the plain names of sealed events in the events file with
+// their ciphertext. Every other line, including comments and times, is kept as written.
+// events must be in file order, as returned by loadEvents.
+func writeSealedNames(path string, events []Event) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	n := 0
+	for i, line := range lines {
+		match := eventNameLineRE.FindStringSubmatch(line)
+		if match == nil || match[2] == "" {
+			continue // loadEvents skips events without a name too
+		}
+		if n >= len(events) {
+			return fmt.Errorf("%s changed since it was loaded", path)
+		}
+		if events[n].Sealed != "" && !isSealed(match[2]) {
+			lines[i] = match[1] + events[n].Sealed + match[3]
+		}
+		n++
+	}
+	if n != len(events) {
+		return fmt.Errorf("%s changed since it was loaded", path)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// sealEvent encrypts the name of a code-phrase event that is not sealed yet.
+func sealEvent(event *Event, passphrase string) error {
+	if event.CodePhrase == "" || event.Sealed != "" {
+		return nil
+	}
+	sealed, err := sealSecret(event.Name, passphrase)
+	if err != nil {
+		return err
+	}
+	event.Sealed = sealed
+	return nil
+}
+
+// unlockEvents decrypts every sealed event with the passphrase. Either all of
+// them are unlocked or, on the first failure, none are.
+// It returns the number of plain-text secret events that still need sealing.
+func unlockEvents(events []Event, passphrase string) (int, error) {
+	plain := 0
+	names := make(map[int]string)
+	for i := range events {
+		if events[i].Sealed == "" {
+			if events[i].CodePhrase != "" {
+				plain++
+			}
+			continue
+		}
+		name, err := openSecret(events[i].Sealed, passphrase)
+		if err != nil {
+			return 0, err
+		}
+		names[i] = name
+	}
+	for i, name := range names {
+		events[i].Name = name
+	}
+	return plain, nil
+}
+
+// eventDisplayName returns the code phrase for secret events until they are unlocked.
+func eventDisplayName(event Event) string {
+	if event.CodePhrase != "" && (event.Name == "" || event.Sealed == "") {
+		return event.CodePhrase
+	}
+	return event.Name
 }
+
 func formatTimeLeft(duration time.Duration) string {
     days := int(duration.Hours() / 24)
     hours := int(duration.Hours()) % 24
@@ -170,3 +295,96 @@ func formatTimeLeft(duration time.Duration) string {
 }
 
 
+
+// hasSealedEvents reports whether any event name is stored encrypted, i.e. a passphrase is in use.
+func hasSealedEvents(events []Event) bool {
+	for _, event := range events {
+		if event.Sealed != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLockedEvents reports whether any event is still waiting to be unlocked.
+func hasLockedEvents(events []Event) bool {
+	for _, event := range events {
+		if event.CodePhrase != "" && (event.Name == "" || event.Sealed == "") {
+			return true
+		}
+	}
+	return false
+}
+
+// upcomingEvent pairs an event with its next occurrence.
+type upcomingEvent struct {
+	Event Event
+	Next  time.Time
+}
+
+// upcomingEvents returns events ordered by their next occurrence.
+// Past one-off events are kept at the end, most recent first.
+func upcomingEvents(events []Event, now time.Time) []upcomingEvent {
+	var upcoming, past []upcomingEvent
+	for _, e := range events {
+		u := upcomingEvent{Event: e, Next: getNextOccurrence(e, now)}
+		if u.Next.Before(now) {
+			past = append(past, u)
+		} else {
+			upcoming = append(upcoming, u)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].Next.Before(upcoming[j].Next) })
+	sort.SliceStable(past, func(i, j int) bool { return past[i].Next.After(past[j].Next) })
+	return append(upcoming, past...)
+}
+
+// eventGroup names the section an occurrence falls into on the countdown screen.
+func eventGroup(next, now time.Time, weekStart time.Weekday) string {
+	switch {
+	case next.Before(now):
+		return "Past"
+	case sameDay(next, now):
+		return "Today"
+	case next.Before(startOfWeek(now, weekStart).AddDate(0, 0, 7)):
+		return "This week"
+	default:
+		return "Later"
+	}
+}
+
+// eventCountdown formats the time until an occurrence for the countdown screen.
+func eventCountdown(duration time.Duration) string {
+	if duration < 0 {
+		return eventAgo(-duration)
+	}
+	days := int(duration.Hours() / 24)
+	hours := int(duration.Hours()) % 24
+	minutes := int(duration.Minutes()) % 60
+	seconds := int(duration.Seconds()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%d d ", days)
+	case hours > 0:
+		return fmt.Sprintf("%d h %d m ", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%d m %d s", minutes, seconds)
+	default:
+		return fmt.Sprintf("%d s", seconds)
+	}
+}
+
+// eventAgo formats how long ago an occurrence passed, in the largest whole unit.
+func eventAgo(ago time.Duration) string {
+	switch {
+	case ago >= 24*time.Hour:
+		return fmt.Sprintf("%d d ago", int(ago.Hours()/24))
+	case ago >= time.Hour:
+		return fmt.Sprintf("%d h ago", int(ago.Hours()))
+	case ago >= time.Minute:
+		return fmt.Sprintf("%d m ago", int(ago.Minutes()))
+	default:
+		return "just now"
+	}
+}
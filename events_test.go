@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWriteSealedNamesKeepsOtherLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.md")
+	original := "# Events\r\n" +
+		"Notes about my events.\r\n" +
+		"1. Event Name: Standup\r\n" +
+		"- Time: 19 August 2025 9:00\r\n" +
+		"- Repeat: Weekly\r\n" +
+		"- Code Phrase:\r\n" +
+		"2. Event Name: Super secret event \r\n" +
+		"- Time: 20 Aug   \r\n" +
+		"- Code Phrase: Secret code phrase\r\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events, err := loadEvents(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events[1].Sealed = sealedPrefix + "abc"
+
+	if err := writeSealedNames(path, events); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	want := strings.Replace(original, "Super secret event \r\n", sealedPrefix+"abc \r\n", 1)
+	if string(got) != want {
+		t.Errorf("events.md =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestWriteSealedNamesRejectsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.md")
+	os.WriteFile(path, []byte("1. Event Name: One\n- Time: 2 Jan\n2. Event Name: Two\n- Time: 3 Jan\n"), 0644)
+	if err := writeSealedNames(path, []Event{{Name: "One"}}); err == nil {
+		t.Error("writeSealedNames accepted a file with more events than loaded")
+	}
+}
+
+func TestUnlockEventsAllOrNothing(t *testing.T) {
+	good, err := sealSecret("Dentist", "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := sealSecret("Party", "different")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []Event{
+		{Sealed: good, CodePhrase: "teeth"},
+		{Sealed: other, CodePhrase: "fun"},
+		{Name: "Plain secret", CodePhrase: "psst"},
+	}
+	if _, err := unlockEvents(events, "hunter2"); err == nil {
+		t.Fatal("unlockEvents succeeded with a passphrase that doesn't open every event")
+	}
+	if events[0].Name != "" {
+		t.Errorf("event unlocked to %q although unlocking failed", events[0].Name)
+	}
+
+	events = events[:1]
+	events = append(events, Event{Name: "Plain secret", CodePhrase: "psst"})
+	plain, err := unlockEvents(events, "hunter2")
+	if err != nil || plain != 1 || events[0].Name != "Dentist" {
+		t.Errorf("unlockEvents = %d, %v, name %q; want 1, nil, %q", plain, err, events[0].Name, "Dentist")
+	}
+}
+
+func TestEventCountdown(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{3*24*time.Hour + 5*time.Hour, "3 d "},
+		{2*time.Hour + 15*time.Minute, "2 h 15 m "},
+		{4*time.Minute + 30*time.Second, "4 m 30 s"},
+		{20 * time.Second, "20 s"},
+		{-30 * time.Second, "just now"},
+		{-45 * time.Minute, "45 m ago"},
+		{-5 * time.Hour, "5 h ago"},
+		{-50 * time.Hour, "2 d ago"},
+	}
+	for _, tt := range tests {
+		if got := eventCountdown(tt.d); got != tt.want {
+			t.Errorf("eventCountdown(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestUpcomingEventsOrder(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	events := []Event{
+		{Name: "Later", DateTime: now.Add(48 * time.Hour)},
+		{Name: "Long past", DateTime: now.Add(-48 * time.Hour)},
+		{Name: "Soon", DateTime: now.Add(time.Hour)},
+		{Name: "Just past", DateTime: now.Add(-time.Hour)},
+		{Name: "Weekly", DateTime: now.Add(-7*24*time.Hour + 2*time.Hour), Repeat: "weekly"},
+	}
+	var got []string
+	for _, u := range upcomingEvents(events, now) {
+		got = append(got, u.Event.Name)
+	}
+	want := "Soon Weekly Later Just past Long past"
+	if strings.Join(got, " ") != want {
+		t.Errorf("upcomingEvents order = %v, want %s", got, want)
+	}
+}
+
+func enterPassphrase(m *model, pass string) {
+	m.passphraseInput.SetValue(pass)
+	m.updatePassphrase(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestNewPassphraseAskedTwice(t *testing.T) {
+	m := model{state: stateUnlockEvents, passphraseInput: textinput.New(), events: []Event{{Name: "Standup"}}}
+	enterPassphrase(&m, "hunter2")
+	if m.secretPassphrase != "" {
+		t.Fatal("a new passphrase was accepted without typing it again")
+	}
+	enterPassphrase(&m, "hunter3")
+	if m.secretPassphrase != "" || m.unlockErr == "" {
+		t.Fatalf("mismatched passphrases: secretPassphrase %q, unlockErr %q", m.secretPassphrase, m.unlockErr)
+	}
+	enterPassphrase(&m, "hunter2")
+	enterPassphrase(&m, "hunter2")
+	if m.secretPassphrase != "hunter2" {
+		t.Errorf("secretPassphrase = %q after typing it twice, want %q", m.secretPassphrase, "hunter2")
+	}
+
+	// Once names are sealed the passphrase is checked against them instead.
+	sealed, err := sealSecret("Dentist", "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = model{state: stateUnlockEvents, passphraseInput: textinput.New(), events: []Event{{Sealed: sealed, CodePhrase: "teeth"}}}
+	enterPassphrase(&m, "hunter2")
+	if m.secretPassphrase != "hunter2" || m.events[0].Name != "Dentist" {
+		t.Errorf("unlocking sealed events: secretPassphrase %q, name %q", m.secretPassphrase, m.events[0].Name)
+	}
+}
+
+func TestReloadedEventsLockAgainOnWrongPassphrase(t *testing.T) {
+	sealed, err := sealSecret("Party", "different")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := model{secretPassphrase: "hunter2"}
+	m.Update(eventsLoadedMsg{events: []Event{{Sealed: sealed, CodePhrase: "fun"}}})
+	if m.secretPassphrase != "" || m.unlockErr == "" {
+		t.Errorf("secretPassphrase %q, unlockErr %q; want the passphrase forgotten and an error", m.secretPassphrase, m.unlockErr)
+	}
+}
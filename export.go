@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const exportDir = "exports"
+
+// writeCSV writes one row per habit per session.
+func writeCSV(path string, entries []logEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"date", "time", "routine", "habit", "planned_minutes", "spent_minutes", "paused_minutes", "status"})
+	for _, e := range entries {
+		w.Write([]string{
+			e.Date.Format("2006-01-02"),
+			e.Date.Format("15:04"),
+			e.Routine,
+			e.Habit,
+			fmt.Sprintf("%.1f", e.Planned.Minutes()),
+			fmt.Sprintf("%.1f", e.Spent.Minutes()),
+			fmt.Sprintf("%.1f", e.Paused.Minutes()),
+			e.Status,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// reportBar is one bar of a chart in the HTML report.
+type reportBar struct {
+	Label   string
+	Value   time.Duration
+	Planned time.Duration
+	Width   float64 // Percent of the chart width
+	PlanX   float64 // Percent position of the planned marker, 0 when there is none
+}
+
+type reportData struct {
+	Title     string
+	Generated string
+	Total     time.Duration
+	Sessions  int
+	Habits    []reportBar
+	Days      []reportBar
+	Entries   []logEntry
+}
+
+// buildReport aggregates entries into the charts of the HTML report.
+func buildReport(title string, entries []logEntry) reportData {
+	data := reportData{
+		Title:     title,
+		Generated: time.Now().Format("2 January 2006 15:04"),
+		Entries:   entries,
+	}
+
+	habitSpent := make(map[string]time.Duration)
+	habitPlanned := make(map[string]time.Duration)
+	daySpent := make(map[string]time.Duration)
+	var habitOrder, dayOrder []string
+	sessions := make(map[time.Time]bool)
+	for _, e := range entries {
+		key := strings.ToLower(e.Habit)
+		if _, ok := habitSpent[key]; !ok {
+			habitOrder = append(habitOrder, e.Habit)
+		}
+		habitSpent[key] += e.Spent
+		habitPlanned[key] += e.Planned
+		day := e.Date.Format("Mon 2 Jan")
+		if _, ok := daySpent[day]; !ok {
+			dayOrder = append(dayOrder, day)
+		}
+		daySpent[day] += e.Spent
+		data.Total += e.Spent
+		sessions[e.Date] = true
+	}
+	data.Sessions = len(sessions)
+
+	for _, h := range habitOrder {
+		key := strings.ToLower(h)
+		data.Habits = append(data.Habits, reportBar{Label: h, Value: habitSpent[key], Planned: habitPlanned[key]})
+	}
+	sort.SliceStable(data.Habits, func(i, j int) bool { return data.Habits[i].Value > data.Habits[j].Value })
+	for _, d := range dayOrder {
+		data.Days = append(data.Days, reportBar{Label: d, Value: daySpent[d]})
+	}
+	scaleBars(data.Habits)
+	scaleBars(data.Days)
+	return data
+}
+
+// scaleBars sets each bar's width relative to the largest value or plan.
+func scaleBars(bars []reportBar) {
+	var max time.Duration
+	for _, b := range bars {
+		if b.Value > max {
+			max = b.Value
+		}
+		if b.Planned > max {
+			max = b.Planned
+		}
+	}
+	if max == 0 {
+		return
+	}
+	for i := range bars {
+		bars[i].Width = float64(bars[i].Value) / float64(max) * 100
+		if bars[i].Planned > 0 {
+			bars[i].PlanX = float64(bars[i].Planned) / float64(max) * 100
+		}
+	}
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"dur": func(d time.Duration) string { return d.Truncate(time.Second).String() },
+	"pct": func(f float64) string { return fmt.Sprintf("%.1f%%", f) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; max-width: 900px; margin: 2em auto; color: #222; }
+h1 { color: #d6409f; }
+.cards { display: flex; gap: 1em; }
+.card { flex: 1; background: #f4f1fb; border-radius: 8px; padding: 1em; }
+.card b { display: block; font-size: 1.6em; color: #3c3ccf; }
+.chart { margin: 1em 0 2em; }
+.row { display: flex; align-items: center; margin: 4px 0; }
+.label { width: 180px; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.track { position: relative; flex: 1; height: 18px; background: #eee; border-radius: 4px; }
+.bar { height: 100%; background: linear-gradient(90deg, #5a56e0, #ee6ff8); border-radius: 4px; }
+.plan { position: absolute; top: -3px; bottom: -3px; width: 2px; background: #222; }
+.value { width: 90px; text-align: right; font-variant-numeric: tabular-nums; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #ddd; }
+.skipped { color: #999; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated {{.Generated}}</p>
+<div class="cards">
+  <div class="card"><b>{{dur .Total}}</b>time spent</div>
+  <div class="card"><b>{{.Sessions}}</b>sessions</div>
+  <div class="card"><b>{{len .Habits}}</b>habits</div>
+</div>
+
+<h2>Time per habit</h2>
+<p>The black line marks the planned time.</p>
+<div class="chart">
+{{range .Habits}}<div class="row"><span class="label">{{.Label}}</span><span class="track"><div class="bar" style="width: {{pct .Width}}"></div>{{if .PlanX}}<span class="plan" style="left: {{pct .PlanX}}"></span>{{end}}</span><span class="value">{{dur .Value}}</span></div>
+{{end}}</div>
+
+<h2>Time per day</h2>
+<div class="chart">
+{{range .Days}}<div class="row"><span class="label">{{.Label}}</span><span class="track"><div class="bar" style="width: {{pct .Width}}"></div></span><span class="value">{{dur .Value}}</span></div>
+{{end}}</div>
+
+<h2>Sessions</h2>
+<table>
+<tr><th>Date</th><th>Routine</th><th>Habit</th><th>Planned</th><th>Spent</th><th>Paused</th><th>Status</th></tr>
+{{range .Entries}}<tr{{if eq .Status "skipped"}} class="skipped"{{end}}><td>{{.Date.Format "2 Jan 15:04"}}</td><td>{{.Routine}}</td><td>{{.Habit}}</td><td>{{dur .Planned}}</td><td>{{dur .Spent}}</td><td>{{dur .Paused}}</td><td>{{.Status}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// writeHTML writes a self-contained report with charts.
+func writeHTML(path, title string, entries []logEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return reportTemplate.Execute(f, buildReport(title, entries))
+}
+
+// exportReport writes base.csv and base.html into dir and returns both paths.
+func exportReport(dir, base, title string, entries []logEntry) (string, string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", err
+	}
+	csvPath := filepath.Join(dir, base+".csv")
+	htmlPath := filepath.Join(dir, base+".html")
+	if err := writeCSV(csvPath, entries); err != nil {
+		return "", "", err
+	}
+	if err := writeHTML(htmlPath, title, entries); err != nil {
+		return "", "", err
+	}
+	return csvPath, htmlPath, nil
+}
+
+// exportSession writes the finished session to the exports directory.
+func (m model) exportSession() (string, string, error) {
+	entries := m.sessionEntries()
+	if len(entries) == 0 {
+		return "", "", fmt.Errorf("nothing to export")
+	}
+	name := routineDisplayName(m.routineFileName)
+	base := fmt.Sprintf("%s %s", entries[0].Date.Format("2006-01-02 1504"), name)
+	return exportReport(exportDir, base, fmt.Sprintf("%s, %s", name, entries[0].Date.Format("2 January 2006")), entries)
+}
+
+// runExport implements `timey export [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-out dir]`,
+// which exports the daily logs in a date range. It defaults to the last seven days.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	now := time.Now()
+	fromStr := fs.String("from", now.AddDate(0, 0, -6).Format("2006-01-02"), "first day to export (YYYY-MM-DD)")
+	toStr := fs.String("to", now.Format("2006-01-02"), "last day to export (YYYY-MM-DD)")
+	out := fs.String("out", exportDir, "directory to write the CSV and HTML files to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", *fromStr, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid -from date: %w", err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", *toStr, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid -to date: %w", err)
+	}
+
+	entries, err := loadLogEntries(logDir, from, to)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no sessions logged between %s and %s", *fromStr, *toStr)
+	}
+
+	title := fmt.Sprintf("timey report %s – %s", from.Format("2 Jan 2006"), to.Format("2 Jan 2006"))
+	csvPath, htmlPath, err := exportReport(*out, fmt.Sprintf("report %s to %s", *fromStr, *toStr), title, entries)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s and %s\n", csvPath, htmlPath)
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportReportFromLog(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "Mon, 19 Oct 2026.md")
+	if err := os.WriteFile(logPath, []byte(testLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := parseLogFile(logPath, time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csvPath, htmlPath, err := exportReport(filepath.Join(dir, "exports"), "october", "October", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"date", "time", "routine", "habit", "planned_minutes", "spent_minutes", "paused_minutes", "status"},
+		{"2026-10-19", "07:00", "Morning", "Wake Up", "0.0", "9.5", "0.0", "done"},
+		{"2026-10-19", "07:00", "Morning", "Stretch", "0.0", "0.0", "0.0", "skipped"},
+		{"2026-10-19", "12:17", "Lunch", "A", "1.0", "1.0", "0.0", "done"},
+		{"2026-10-19", "12:17", "Lunch", "B", "1.0", "0.0", "0.0", "skipped"},
+		{"2026-10-19", "12:17", "Lunch", "C", "1.0", "0.3", "0.1", "unfinished"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows %v, want %d", len(rows), rows, len(want))
+	}
+	for i := range want {
+		if strings.Join(rows[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+
+	report := buildReport("October", entries)
+	if report.Total != 10*time.Minute+50*time.Second || report.Sessions != 2 || len(report.Days) != 1 {
+		t.Errorf("report total %s, %d sessions, %d days; want 10m50s, 2 and 1", report.Total, report.Sessions, len(report.Days))
+	}
+	if top := report.Habits[0]; top.Label != "Wake Up" || top.Width != 100 {
+		t.Errorf("longest habit bar = %+v, want Wake Up at full width", top)
+	}
+	if html, err := os.ReadFile(htmlPath); err != nil || !strings.Contains(string(html), "<h1>October</h1>") {
+		t.Errorf("HTML report missing its title (%v)", err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// bigGlyphs are the characters of the focus-mode clock, five rows high.
+var bigGlyphs = map[rune][]string{
+	'0': {"█████", "█   █", "█   █", "█   █", "█████"},
+	'1': {"  ██ ", "   █ ", "   █ ", "   █ ", "  ███"},
+	'2': {"█████", "    █", "█████", "█    ", "█████"},
+	'3': {"█████", "    █", " ████", "    █", "█████"},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "█████", "    █", "█████"},
+	'6': {"█████", "█    ", "█████", "█   █", "█████"},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {"█████", "█   █", "█████", "█   █", "█████"},
+	'9': {"█████", "█   █", "█████", "    █", "█████"},
+	':': {"   ", " █ ", "   ", " █ ", "   "},
+	'-': {"     ", "     ", "█████", "     ", "     "},
+}
+
+// bigGlyphHeight is the number of rows of each glyph at scale 1.
+const bigGlyphHeight = 5
+
+// focusChrome is the number of lines the focus view uses besides the digits:
+// padding, title, progress bar, status and help.
+const focusChrome = 10
+
+// bigTextWidth returns how many columns s takes at scale 1.
+func bigTextWidth(s string) int {
+	width := 0
+	for i, r := range s {
+		if i > 0 {
+			width++ // Gap between glyphs
+		}
+		width += len([]rune(bigGlyphs[r][0]))
+	}
+	return width
+}
+
+// bigText renders s with bigGlyphs, each cell repeated scale times across and down.
+// Characters without a glyph are left out.
+func bigText(s string, scale int) string {
+	scale = max(scale, 1)
+	rows := make([]string, 0, bigGlyphHeight*scale)
+	for y := 0; y < bigGlyphHeight; y++ {
+		var row strings.Builder
+		for i, r := range s {
+			glyph, ok := bigGlyphs[r]
+			if !ok {
+				continue
+			}
+			if i > 0 {
+				row.WriteString(strings.Repeat(" ", scale))
+			}
+			for _, cell := range glyph[y] {
+				row.WriteString(strings.Repeat(string(cell), scale))
+			}
+		}
+		for i := 0; i < scale; i++ {
+			rows = append(rows, row.String())
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// bigClockScale returns the largest scale at which text fits in width by height,
+// or 0 when even scale 1 doesn't fit.
+func bigClockScale(text string, width, height int) int {
+	w := bigTextWidth(text)
+	scale := 0
+	for s := 1; w*s <= width && bigGlyphHeight*s <= height; s++ {
+		scale = s
+	}
+	return scale
+}
+
+// formatClock formats a duration for the focus clock as MM:SS, or H:MM:SS from an hour.
+func formatClock(d time.Duration) string {
+	d = max(d, 0).Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// toggleFocusMode switches the running view between the normal and the full-screen focus display.
+// The checklist starts hidden each time focus mode is entered.
+func (m *model) toggleFocusMode() {
+	m.focusMode = !m.focusMode
+	m.focusChecklist = false
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// goalsPath is where weekly and monthly habit targets are read from.
+const goalsPath = "config/goals.md"
+
+// Goal is a weekly or monthly target for a habit, either time spent or times completed.
+type Goal struct {
+	Habit  string
+	Period string        // "week" or "month"
+	Target time.Duration // Time goal, zero for a completion goal
+	Count  int           // Completion goal, zero for a time goal
+}
+
+// goalProgress is a goal together with what the logs count towards it.
+type goalProgress struct {
+	Goal
+	Spent time.Duration
+	Done  int
+}
+
+var (
+	goalRE      = regexp.MustCompile(`(?i)^-\s*(.+?):\s*(.+?)\s*(?:per|a|every|/)\s*(week|month)$`)
+	goalCountRE = regexp.MustCompile(`(?i)^(\d+)\s*(?:x|times?|sessions?)$`)
+)
+
+// loadGoals reads lines like "- Exercise: 150 min per week" or
+// "- Reading: 5 times per month". A missing file means no goals.
+func loadGoals(path string) ([]Goal, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var goals []Goal
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := goalRE.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		goal := Goal{
+			Habit:  strings.TrimSpace(match[1]),
+			Period: strings.ToLower(match[3]),
+		}
+		if count := goalCountRE.FindStringSubmatch(match[2]); count != nil {
+			goal.Count, _ = strconv.Atoi(count[1])
+		} else if d, err := parseDuration(match[2]); err == nil {
+			goal.Target = d
+		} else {
+			continue
+		}
+		goals = append(goals, goal)
+	}
+	return goals, scanner.Err()
+}
+
+// goalPeriod returns the first and last day of the week (starting on weekStart) or month containing now.
+func goalPeriod(period string, now time.Time, weekStart time.Weekday) (time.Time, time.Time) {
+	day := startOfDay(now)
+	if period == "month" {
+		first := day.AddDate(0, 0, 1-day.Day())
+		return first, first.AddDate(0, 1, -1)
+	}
+	first := startOfWeek(day, weekStart)
+	return first, first.AddDate(0, 0, 6)
+}
+
+// goalsProgress adds up the logged sessions of the current week or month for each goal.
+// Time counts whatever the habit's status, completions only count habits marked done.
+func goalsProgress(goals []Goal, dir string, now time.Time, weekStart time.Weekday) ([]goalProgress, error) {
+	var progress []goalProgress
+	for _, goal := range goals {
+		from, to := goalPeriod(goal.Period, now, weekStart)
+		entries, err := loadLogEntries(dir, from, to)
+		if err != nil {
+			return nil, err
+		}
+		p := goalProgress{Goal: goal}
+		for _, e := range entries {
+			if !strings.EqualFold(e.Habit, goal.Habit) {
+				continue
+			}
+			p.Spent += e.Spent
+			if e.Status == habitDone.String() {
+				p.Done++
+			}
+		}
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
+// fraction returns how far along the goal is, capped at 1.
+func (p goalProgress) fraction() float64 {
+	var f float64
+	if p.Count > 0 {
+		f = float64(p.Done) / float64(p.Count)
+	} else if p.Target > 0 {
+		f = float64(p.Spent) / float64(p.Target)
+	}
+	return min(f, 1)
+}
+
+// renderGoals draws a progress bar per goal in the same style as the greet bars.
+func renderGoals(progress []goalProgress) string {
+	var b strings.Builder
+	b.WriteString("Goals\n")
+	for _, p := range progress {
+		var amount string
+		if p.Count > 0 {
+			amount = fmt.Sprintf("%d/%d this %s", p.Done, p.Count, p.Period)
+		} else {
+			amount = fmt.Sprintf("%s/%s this %s", p.Spent.Truncate(time.Minute), p.Target, p.Period)
+		}
+		length := 30
+		left := int(float64(length) * p.fraction())
+		b.WriteString(fmt.Sprintf("\n %s: %s\n [%s%s] %.0f%% \n",
+			truncateLabel(p.Habit, timelineLabelWidth),
+			amount,
+			strings.Repeat("■", left),
+			strings.Repeat("□", length-left),
+			p.fraction()*100,
+		))
+	}
+	return b.String()
+}
+
+// refreshGoals recomputes goal progress from the logs, e.g. after a session is saved.
+func (m *model) refreshGoals() {
+	goals, err := loadGoals(goalsPath)
+	if err != nil {
+		m.goals = nil
+		return
+	}
+	m.goals, _ = goalsProgress(goals, logDir, time.Now(), m.config.WeekStart)
+}
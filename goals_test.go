@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadGoals(t *testing.T) {
+	content := "# Goals\n" +
+		"- Exercise: 150 min per week\n" +
+		"- Reading: 5 times per month\n" +
+		"- Meditate: 3x a week\n" +
+		"- Journal: 1 session every month\n" +
+		"- Walk: 2h/week\n" +
+		"- Stretch: 10 min per day\n" +
+		"- Sleep: a lot per week\n" +
+		"Exercise: 30 min per week\n"
+	path := filepath.Join(t.TempDir(), "goals.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadGoals(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Goal{
+		{Habit: "Exercise", Period: "week", Target: 150 * time.Minute},
+		{Habit: "Reading", Period: "month", Count: 5},
+		{Habit: "Meditate", Period: "week", Count: 3},
+		{Habit: "Journal", Period: "month", Count: 1},
+		{Habit: "Walk", Period: "week", Target: 2 * time.Hour},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("goal %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if goals, err := loadGoals(filepath.Join(t.TempDir(), "missing.md")); err != nil || goals != nil {
+		t.Errorf("missing goals file = %v, %v; want no goals and no error", goals, err)
+	}
+}
+
+func TestGoalsProgress(t *testing.T) {
+	dir := t.TempDir()
+	// Sessions on Sunday the 18th and Monday the 19th of October.
+	for _, name := range []string{"Sun, 18 Oct 2026.md", "Mon, 19 Oct 2026.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(testLog), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	goals := []Goal{
+		{Habit: "wake up", Period: "week", Target: time.Hour},
+		{Habit: "A", Period: "week", Count: 3},
+		{Habit: "C", Period: "month", Target: time.Hour},
+		{Habit: "Yoga", Period: "week", Count: 2},
+	}
+	monday := time.Date(2026, 10, 19, 21, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		weekStart time.Weekday
+		want      []goalProgress
+	}{
+		// The week starting Monday leaves Sunday's log out of the weekly goals.
+		{time.Monday, []goalProgress{
+			{Goal: goals[0], Spent: 9*time.Minute + 30*time.Second, Done: 1},
+			{Goal: goals[1], Spent: time.Minute, Done: 1},
+			{Goal: goals[2], Spent: 40 * time.Second},
+			{Goal: goals[3]},
+		}},
+		{time.Sunday, []goalProgress{
+			{Goal: goals[0], Spent: 19 * time.Minute, Done: 2},
+			{Goal: goals[1], Spent: 2 * time.Minute, Done: 2},
+			{Goal: goals[2], Spent: 40 * time.Second},
+			{Goal: goals[3]},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := goalsProgress(goals, dir, monday, tt.weekStart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("week from %s: goal %s = %+v, want %+v", tt.weekStart, goals[i].Habit, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestGoalPeriod(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		period    string
+		weekStart time.Weekday
+		from, to  time.Time
+	}{
+		{"week", time.Monday, time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local), time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)},
+		{"week", time.Sunday, time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local), time.Date(2026, 10, 24, 0, 0, 0, 0, time.Local)},
+		{"month", time.Monday, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local), time.Date(2026, 10, 31, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		from, to := goalPeriod(tt.period, sunday, tt.weekStart)
+		if !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("goalPeriod(%s, %s) = %s to %s, want %s to %s", tt.period, tt.weekStart,
+				from.Format("2 Jan"), to.Format("2 Jan"), tt.from.Format("2 Jan"), tt.to.Format("2 Jan"))
+		}
+	}
+}
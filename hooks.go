@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Executables in hooksDir are run on session lifecycle events.
+const (
+	hooksDir     = "hooks"
+	hooksLog     = "hooks/hooks.log"
+	hooksTimeout = 10 * time.Second
+)
+
+// Lifecycle events passed to hooks as their first argument and in the payload.
+const (
+	hookSessionStart = "session_start"
+	hookHabitStart   = "habit_start"
+	hookHabitEnd     = "habit_end"
+	hookPause        = "pause"
+	hookResume       = "resume"
+	hookSessionStop  = "session_stop"
+)
+
+// hookPayload is the JSON written to a hook's stdin. Durations are in seconds.
+type hookPayload struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Routine string    `json:"routine"`
+	Habit   string    `json:"habit,omitempty"`
+	Planned int       `json:"planned,omitempty"`
+	Elapsed int       `json:"elapsed"`
+	Paused  int       `json:"paused"`
+	Status  string    `json:"status,omitempty"` // Habit status on habit_end
+	Break   bool      `json:"break,omitempty"`  // Pause and resume of a break between habits
+}
+
+// hookPayloadFor describes the event for the current habit, or for the whole
+// session on session_start and session_stop.
+func (m model) hookPayloadFor(event string) hookPayload {
+	p := hookPayload{
+		Event:   event,
+		Time:    time.Now(),
+		Routine: routineDisplayName(m.routineFileName),
+	}
+	if event == hookSessionStart || event == hookSessionStop {
+		p.Elapsed = int(m.calculateTotalElapsed().Seconds())
+		p.Paused = int(m.totalPaused.Seconds())
+		return p
+	}
+	if m.current < len(m.routines) {
+		r := m.routines[m.current]
+		planned, _ := parseDuration(r.Time)
+		p.Habit = r.Title
+		p.Planned = int(planned.Seconds())
+		p.Elapsed = int(m.habitElapsed().Seconds())
+		p.Paused = int((r.Paused + m.visitPaused).Seconds())
+		if event == hookHabitEnd {
+			p.Status = r.Status.String()
+		}
+	}
+	p.Break = m.state == statePausing
+	return p
+}
+
+// hookRun is one hook invocation waiting in hookQueue.
+type hookRun struct {
+	path    string
+	event   string
+	payload []byte
+}
+
+// Hooks run one at a time, in the order the events happened, so a script
+// toggling something on pause and resume never sees them swapped.
+var (
+	hookQueue     = make(chan hookRun, 64)
+	hookQueueOnce sync.Once
+	hookDone      = make(chan struct{}) // closed once the queue is drained after waitForHooks
+	hookMu        sync.Mutex            // guards hookClosed and sending on hookQueue
+	hookClosed    bool
+)
+
+// runHooks queues every executable in hooks/ for the event, with the event
+// name as its argument and the payload on stdin. It never blocks the TUI:
+// when the queue is full the event is dropped.
+func (m model) runHooks(event string) {
+	files, err := os.ReadDir(hooksDir)
+	if err != nil {
+		return
+	}
+	payload, err := json.Marshal(m.hookPayloadFor(event))
+	if err != nil {
+		return
+	}
+
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	if hookClosed {
+		return
+	}
+	hookQueueOnce.Do(func() { go hookWorker() })
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil || file.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		select {
+		case hookQueue <- hookRun{filepath.Join(hooksDir, file.Name()), event, payload}:
+		default:
+			logHookError(filepath.Join(hooksDir, file.Name()), event, fmt.Errorf("hook queue full, event dropped"), nil)
+		}
+	}
+}
+
+// hookWorker runs queued hooks. Failures are appended to hooks/hooks.log so they don't disturb the TUI.
+func hookWorker() {
+	defer close(hookDone)
+	for run := range hookQueue {
+		ctx, cancel := context.WithTimeout(context.Background(), hooksTimeout)
+		cmd := exec.CommandContext(ctx, run.path, run.event)
+		cmd.Stdin = bytes.NewReader(run.payload)
+		cmd.Env = append(os.Environ(), "TIMEY_EVENT="+run.event)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logHookError(run.path, run.event, err, out)
+		}
+		cancel()
+	}
+}
+
+// waitForHooks stops queueing hooks and waits up to timeout for the queued ones
+// to run, so quitting right after stopping a session still runs its habit_end
+// and session_stop hooks.
+func waitForHooks(timeout time.Duration) {
+	hookMu.Lock()
+	if hookClosed {
+		hookMu.Unlock()
+		return
+	}
+	hookClosed = true
+	close(hookQueue)
+	hookMu.Unlock()
+
+	// Without a worker nothing was queued, and there is nothing to wait for.
+	hookQueueOnce.Do(func() { close(hookDone) })
+	select {
+	case <-hookDone:
+	case <-time.After(timeout):
+	}
+}
+
+// logHookError records a failed hook run.
+func logHookError(path, event string, err error, out []byte) {
+	f, ferr := os.OpenFile(hooksLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if ferr != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%s %s %s: %v\n%s", time.Now().Format(time.RFC3339), path, event, err, out)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHooksRunInOrderBeforeQuitting(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// The hook appends one line per event: its argument, then the payload.
+	script := "#!/bin/sh\nprintf '%s ' \"$1\" >> hooks.out\ncat >> hooks.out\necho >> hooks.out\n"
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hooksDir, "record"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := testSession(habitPending, habitPending)
+	m.routineFileName = "Morning.md"
+	m.startHabit()
+	m.pauseHabit()
+	m.resumeHabit()
+	// Stopping with q runs habit_end and session_stop, quitting must not drop them.
+	m = m.stopSession()
+	waitForHooks(5 * time.Second)
+
+	out, err := os.ReadFile("hooks.out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	var payloads []hookPayload
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		event, data, _ := strings.Cut(line, " ")
+		var p hookPayload
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			t.Fatalf("payload of %s: %v in %q", event, err, data)
+		}
+		if p.Event != event {
+			t.Errorf("hook argument %q, payload event %q", event, p.Event)
+		}
+		events = append(events, event)
+		payloads = append(payloads, p)
+	}
+
+	want := []string{hookSessionStart, hookHabitStart, hookPause, hookResume, hookHabitEnd, hookSessionStop}
+	if strings.Join(events, " ") != strings.Join(want, " ") {
+		t.Fatalf("hooks ran for %v, want %v", events, want)
+	}
+	for _, p := range payloads {
+		if p.Routine != "Morning" {
+			t.Errorf("%s: routine %q, want Morning", p.Event, p.Routine)
+		}
+	}
+	if end := payloads[4]; end.Habit != "A" || end.Planned != 60 || end.Status == "" {
+		t.Errorf("habit_end payload = %+v, want habit A planned for 60s with a status", end)
+	}
+	if stop := payloads[5]; stop.Habit != "" {
+		t.Errorf("session_stop payload names habit %q, want none", stop.Habit)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	logDir        = "logging"
+	logDateLayout = "Mon, 2 Jan 2006"
+)
+
+// logEntry is one habit of one session, as read back from the daily logs.
+type logEntry struct {
+	Date    time.Time // Day of the log with the session's time of day
+	Routine string
+	Habit   string
+	Planned time.Duration
+	Spent   time.Duration
+	Paused  time.Duration
+	Status  string // "done", "skipped" or "unfinished"
+}
+
+var (
+	logSessionRE = regexp.MustCompile(`^## Session - (\d{1,2}:\d{2}:\d{2})`)
+	logRoutineRE = regexp.MustCompile(`^Routine:\s*(.*)$`)
+	logHabitRE   = regexp.MustCompile(`^### (.+)$`)
+	logSpentRE   = regexp.MustCompile(`^Time Spent:\s*(\S+)`)
+	logPlannedRE = regexp.MustCompile(`^Planned:\s*(\S+)`)
+	logPausedRE  = regexp.MustCompile(`^Paused:\s*(\S+)`)
+	logStatusRE  = regexp.MustCompile(`^Status:\s*(\w+)`)
+)
+
+// logSections are the "### " headings of a session log that aren't habits.
+var logSections = map[string]bool{"Planned vs actual": true, "Timeline": true}
+
+// loadLogEntries reads every daily log from dir whose date falls within [from, to].
+func loadLogEntries(dir string, from, to time.Time) ([]logEntry, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	from, to = startOfDay(from), startOfDay(to)
+
+	var entries []logEntry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		day, err := time.ParseInLocation(logDateLayout, strings.TrimSuffix(file.Name(), ".md"), time.Local)
+		if err != nil || day.Before(from) || day.After(to) {
+			continue
+		}
+		dayEntries, err := parseLogFile(filepath.Join(dir, file.Name()), day)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, dayEntries...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Date.Before(entries[j].Date) })
+	return entries, nil
+}
+
+// parseLogFile reads the habits of every session in one daily log.
+// A "### " heading only counts as a habit once its "Time Spent:" line is seen,
+// and the planned vs actual table and the timeline are skipped.
+func parseLogFile(path string, day time.Time) ([]logEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []logEntry
+	var current *logEntry
+	sessionTime := day
+	routine := ""
+	heading := ""
+	inCode := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		if match := logSessionRE.FindStringSubmatch(line); match != nil {
+			if t, err := time.Parse("15:04:05", match[1]); err == nil {
+				sessionTime = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, day.Location())
+			}
+			routine, heading, current = "", "", nil
+		} else if match := logRoutineRE.FindStringSubmatch(line); match != nil {
+			routine = strings.TrimSpace(match[1])
+		} else if match := logHabitRE.FindStringSubmatch(line); match != nil {
+			heading, current = strings.TrimSpace(match[1]), nil
+			if logSections[heading] {
+				heading = ""
+			}
+		} else if match := logSpentRE.FindStringSubmatch(line); match != nil && heading != "" {
+			spent, _ := time.ParseDuration(match[1])
+			entries = append(entries, logEntry{
+				Date:    sessionTime,
+				Routine: routine,
+				Habit:   heading,
+				Spent:   spent,
+				Status:  "done", // Logs written before statuses were tracked
+			})
+			current = &entries[len(entries)-1]
+		} else if current == nil {
+			continue
+		} else if match := logPlannedRE.FindStringSubmatch(line); match != nil {
+			current.Planned, _ = time.ParseDuration(match[1])
+		} else if match := logPausedRE.FindStringSubmatch(line); match != nil {
+			current.Paused, _ = time.ParseDuration(match[1])
+		} else if match := logStatusRE.FindStringSubmatch(line); match != nil {
+			current.Status = match[1]
+		} else if line == "Skipped: yes" {
+			current.Status = "skipped"
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// sessionEntries turns the habits of the finished session into log entries.
+func (m model) sessionEntries() []logEntry {
+	var entries []logEntry
+	date := time.Now()
+	if len(m.sessions) > 0 {
+		date = m.sessions[0].Start
+	}
+	for _, r := range m.routines {
+		if !r.visited() {
+			continue
+		}
+		planned, _ := parseDuration(r.Time)
+		entries = append(entries, logEntry{
+			Date:    date,
+			Routine: routineDisplayName(m.routineFileName),
+			Habit:   r.Title,
+			Planned: planned,
+			Spent:   r.Elapsed.Truncate(time.Second),
+			Paused:  r.Paused.Truncate(time.Second),
+			Status:  r.Status.String(),
+		})
+	}
+	return entries
+}
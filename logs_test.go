@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testLog is a daily log as saveLog writes it: a session from before statuses
+// were tracked, then one with the planned vs actual table and the timeline. Lines in
+// the timeline's code block are never read as habits, even one that looks like it.
+const testLog = `
+---
+
+## Session - 07:00:00
+Routine: Morning
+
+### Wake Up
+Time Spent: 9m30s
+
+### Stretch
+Time Spent: 0s
+Skipped: yes
+
+---
+
+## Session - 12:17:05
+Routine: Lunch
+
+### A
+Time Spent: 1m0s
+Planned: 1m0s
+Status: done
+Checklist:
+- [x] Stretch (1m11s / 1m0s)
+
+### B
+Time Spent: 0s
+Planned: 1m0s
+Status: skipped
+Checklist:
+
+### C
+Time Spent: 20s
+Planned: 1m0s
+Paused: 5s
+Status: unfinished
+Checklist:
+
+Total Paused: 5s
+
+Segments:
+- 12:15:04–12:16:04 A: 1m0s
+- 12:16:04–12:16:04 B: 0s, skipped
+- 12:16:04–12:16:29 C: 20s, paused 5s
+
+### Planned vs actual
+
+| Habit | Planned | Actual | Difference |
+|---|---|---|---|
+| A | 1m0s | 1m0s | +0s |
+| B (skipped) | 1m0s | 0s | -1m0s |
+| C | 1m0s | 20s | -40s |
+
+### Timeline
+
+` + "```" + `
+A                  ████████████████████████████████████████████████████·
+Time Spent: 99m
+                   12:15                                           12:17
+` + "```" + `
+`
+
+func TestParseLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Mon, 19 Oct 2026.md")
+	if err := os.WriteFile(path, []byte(testLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	got, err := parseLogFile(path, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	morning, lunch := day.Add(7*time.Hour), day.Add(12*time.Hour+17*time.Minute+5*time.Second)
+	want := []logEntry{
+		{Date: morning, Routine: "Morning", Habit: "Wake Up", Spent: 9*time.Minute + 30*time.Second, Status: "done"},
+		{Date: morning, Routine: "Morning", Habit: "Stretch", Status: "skipped"},
+		{Date: lunch, Routine: "Lunch", Habit: "A", Planned: time.Minute, Spent: time.Minute, Status: "done"},
+		{Date: lunch, Routine: "Lunch", Habit: "B", Planned: time.Minute, Status: "skipped"},
+		{Date: lunch, Routine: "Lunch", Habit: "C", Planned: time.Minute, Spent: 20 * time.Second, Paused: 5 * time.Second, Status: "unfinished"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadLogEntriesDateRange(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Sun, 18 Oct 2026.md", "Mon, 19 Oct 2026.md", "Tue, 20 Oct 2026.md", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(testLog), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	from := time.Date(2026, 10, 19, 15, 0, 0, 0, time.Local)
+	entries, err := loadLogEntries(dir, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 10 || entries[0].Date.Day() != 19 || entries[9].Date.Day() != 20 {
+		t.Errorf("got %d entries from %s to %s, want 10 from the 19th and 20th",
+			len(entries), entries[0].Date.Format("2 Jan"), entries[len(entries)-1].Date.Format("2 Jan"))
+	}
+	if entries, err := loadLogEntries(filepath.Join(dir, "missing"), from, from); err != nil || entries != nil {
+		t.Errorf("missing log dir = %v, %v; want no entries and no error", entries, err)
+	}
+}
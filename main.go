@@ -25,6 +25,12 @@ import (
 // Init initializes the application. It returns a command to be executed.
 // This method is required by the tea.Model interface.
 func (m model) Init() tea.Cmd {
+	// Scheduled routines are checked whatever screen is showing.
+	return tea.Batch(scheduleTick(), m.initState())
+}
+
+// initState returns the commands the starting screen needs.
+func (m model) initState() tea.Cmd {
 	if m.state == stateAddRoutine {
 		return textinput.Blink
 	}
@@ -76,6 +82,7 @@ func newAppModel() (model, error) {
 	l.SetShowHelp(false)
 
 	p := progress.New(progress.WithDefaultGradient())
+	ip := progress.New(progress.WithScaledGradient("#FF7CCB", "#FDFF8C"), progress.WithoutPercentage())
 	s := spinner.New(spinner.WithSpinner(spinner.Jump))
 	s.Style = spinnerStyle
 
@@ -106,7 +113,7 @@ func newAppModel() (model, error) {
 	sCountdown.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
 	// Load quotes
-	quotes, err := loadQuotes("quotes/quotes.md")
+	quotes, quoteWarnings, err := loadQuotes(quotesPath)
 	if err != nil {
 		// If quotes directory doesn't exist, create it
 		if os.IsNotExist(err) {
@@ -128,6 +135,14 @@ func newAppModel() (model, error) {
         }
     }
 
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return model{}, fmt.Errorf("could not read config: %w", err)
+	}
+
+	avp := viewport.New(0, 0)
+	avp.Style = summaryViewportStyle
+
 	evp := viewport.New(0, 0)
 	evp.Style = summaryViewportStyle
     // event builder text input setup
@@ -136,6 +151,20 @@ func newAppModel() (model, error) {
 	eti.Prompt = focusedStyle.Render(ti.Placeholder) + " "
 	eti.Cursor.Style = focusedStyle
 
+	// Ad-hoc habit input for the session outline
+	oti := textinput.New()
+	oti.Placeholder = "Habit and duration (e.g. Call mom 10m):"
+	oti.Prompt = focusedStyle.Render(oti.Placeholder) + " "
+	oti.Cursor.Style = focusedStyle
+
+	// Passphrase input for sealing and unlocking secret events
+	pti := textinput.New()
+	pti.Placeholder = "Passphrase:"
+	pti.Prompt = focusedStyle.Render(pti.Placeholder) + " "
+	pti.EchoMode = textinput.EchoPassword
+	pti.EchoCharacter = '•'
+	pti.Cursor.Style = focusedStyle
+
 	// Glamour renderer for event builder viewport
 	eventBuilderRenderer, err := glamour.NewTermRenderer(
 		glamour.WithAutoStyle(),
@@ -147,25 +176,38 @@ func newAppModel() (model, error) {
 
 	
 
-	return model{
+	m := model{
 		state:              stateCountdown, // Start with the countdown
 		fileList:           l,
 		progress:           p,
+		itemProgress:       ip,
 		spinner:            s,
 		viewport:           vp,
 		textInput:          ti,
 		builderStage:       stageTitle,
 		renderer:           builderRenderer,
-		countdownRemaining: timeLeftToday(),
 		countdownSpinner:   sCountdown,
-		countdownGreetText: greet(now),
+		countdownGreetText: greet(now, cfg),
 		quotes:             quotes,
+		quoteWarnings:      quoteWarnings,
+		quoteHistory:       loadQuoteHistory(quoteHistoryPath),
 		events:             events,
 		eventViewport:           evp,
         eventTextInput:          eti,
         eventBuilderStage:       eventStageName,
         eventRenderer:           eventBuilderRenderer,   
-	}, nil
+        passphraseInput:         pti,
+        outlineInput:            oti,
+        quoteInputs:             newQuoteInputs(),
+        agendaViewport:          avp,
+        config:                  cfg,
+        countdownViewport:       viewport.New(0, 0),
+        schedules:               loadRoutineSchedules("routines"),
+        lastScheduleCheck:       now,
+	}
+	m.refreshCountdowns()
+	m.refreshGoals()
+	return m, nil
 }
 
 
@@ -175,6 +217,15 @@ func (m *model) updatePaneSizes() {
         return
     }
 
+    // The countdown event list scrolls below the greeting once it outgrows the terminal.
+    countdownHeader := lipgloss.Height(m.countdownHeader()) + 5 + max(len(m.countdowns), 1)
+    if len(m.schedules) > 0 {
+        countdownHeader += 2 // "Next routine" line
+    }
+    m.countdownViewport.Width = m.width
+    m.countdownViewport.Height = max(m.height-countdownHeader-3, 3)
+    m.refreshCountdownEvents()
+
     if m.state == stateFilePicker || m.state == stateRoutineView {
         listWidth := m.width / 2
         contentWidth := m.width - listWidth
@@ -199,6 +250,9 @@ func (m *model) updatePaneSizes() {
             glamour.WithAutoStyle(),
             glamour.WithWordWrap(m.viewport.Width - 2), // Account for glamour's internal gutter
         )
+	 } else if m.state == stateAgenda {
+        m.agendaViewport.Width = m.width - m.agendaViewport.Style.GetHorizontalFrameSize()
+        m.agendaViewport.Height = m.height - m.agendaViewport.Style.GetVerticalFrameSize() - 3 // Leave room for help line
 	 } else if m.state == stateAddEvent {
         // Adjust viewport for routine builder
         builderViewportWidth := m.width - m.viewport.Style.GetHorizontalFrameSize()
@@ -215,15 +269,58 @@ func (m *model) updatePaneSizes() {
     }
 
 
+// refreshCountdownEvents re-renders the event list shown on the countdown screen.
+func (m *model) refreshCountdownEvents() {
+    m.countdownViewport.SetContent(renderEventList(*m))
+}
+
+
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	model, err := newAppModel()
 	if err != nil {
 		fmt.Println("Error:", err)
 		os.Exit(1)
 	}
 
-	if _, err := tea.NewProgram(&model).Run(); err != nil { // Pass pointer to model
+	p := tea.NewProgram(&model) // Pass pointer to model
+
+	if model.config.API != "" {
+		l, err := listenAPI(model.config.API)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		defer l.Close()
+		go serveAPI(l, p)
+	}
+
+	_, err = p.Run()
+	// Let the hooks of a session stopped right before quitting finish.
+	waitForHooks(hooksTimeout)
+	if err != nil {
 		fmt.Println("Error:", err)
 		os.Exit(1)
 	}
 }
+
+// runCommand runs a command-line subcommand instead of the TUI.
+func runCommand(name string, args []string) error {
+	switch name {
+	case "export":
+		return runExport(args)
+	case "status":
+		return runStatus(args)
+	case "quotes":
+		return runQuotes(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openOutline shows the session outline on top of a running or waiting habit.
+// Time spent in the outline is not counted towards the habit.
+func (m *model) openOutline() tea.Cmd {
+	if m.state == stateRunning {
+		m.flushItemTime()
+		m.elapsed += time.Since(m.startTime)
+	}
+	m.outlineReturn = m.state
+	m.outlineCursor = m.current
+	m.outlineInserting = false
+	m.state = stateOutline
+	return nil
+}
+
+// closeOutline goes back to the habit the session is on.
+func (m *model) closeOutline() tea.Cmd {
+	switch {
+	case m.current >= len(m.routines):
+		m.state = stateReadyToStart
+		return nil
+	case m.outlineReturn == stateRunning:
+		m.state = stateRunning
+		m.startTime = time.Now()
+		m.itemStart = m.startTime
+		return tick()
+	case m.outlineReturn == statePaused:
+		m.state = statePaused
+		return tick()
+	}
+	m.state = stateReadyToStart
+	return nil
+}
+
+// restoreOutlineReturn puts the open habit back in the state the outline was opened
+// from, so the session engine can close its visit correctly.
+func (m *model) restoreOutlineReturn() {
+	m.state = m.outlineReturn
+	if m.state == stateRunning {
+		m.startTime = time.Now()
+		m.itemStart = m.startTime
+	}
+}
+
+// updateOutline handles keys for the session outline overlay.
+func (m *model) updateOutline(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.outlineInserting {
+		return m.updateOutlineInsert(msg)
+	}
+
+	switch msg.String() {
+	case "esc", "o", "q":
+		return m, m.closeOutline()
+
+	case "up", "k":
+		if m.outlineCursor > 0 {
+			m.outlineCursor--
+		}
+
+	case "down", "j":
+		if m.outlineCursor < len(m.routines)-1 {
+			m.outlineCursor++
+		}
+
+	case "enter":
+		if m.outlineCursor == m.current {
+			return m, m.closeOutline()
+		}
+		// Jump to the selected habit.
+		m.restoreOutlineReturn()
+		m.goToHabit(m.outlineCursor)
+		return m, nil
+
+	case "x":
+		if m.outlineCursor == m.current {
+			m.restoreOutlineReturn()
+			m.closeVisit(true)
+			m.state = stateOutline
+			if m.moveToNextPending(); m.state == stateStopped {
+				return m, nil
+			}
+			m.state = stateOutline
+			m.outlineCursor = m.current
+			m.outlineReturn = stateReadyToStart
+		} else if m.routines[m.outlineCursor].Status == habitPending {
+			now := time.Now()
+			m.routines[m.outlineCursor].Status = habitSkipped
+			m.sessions = append(m.sessions, Session{
+				RoutineTitle: m.routines[m.outlineCursor].Title,
+				Start:        now,
+				End:          now,
+				Skipped:      true,
+			})
+		}
+
+	case "m":
+		// Move the selected habit one place later.
+		i := m.outlineCursor
+		if i < len(m.routines)-1 {
+			m.routines[i], m.routines[i+1] = m.routines[i+1], m.routines[i]
+			switch m.current {
+			case i:
+				m.current = i + 1
+			case i + 1:
+				m.current = i
+			}
+			m.outlineCursor = i + 1
+		}
+
+	case "i":
+		m.outlineInserting = true
+		m.outlineInput.Reset()
+		m.outlineInput.Focus()
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+// updateOutlineInsert reads an ad-hoc habit like "Call mom 10m" and inserts it after the cursor.
+func (m *model) updateOutlineInsert(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.outlineInserting = false
+		m.outlineInput.Blur()
+		return m, nil
+	case "enter":
+		habit, err := parseAdHocHabit(m.outlineInput.Value())
+		if err != nil {
+			m.outlineErr = err.Error()
+			return m, nil
+		}
+		at := m.outlineCursor + 1
+		if at > len(m.routines) {
+			at = len(m.routines)
+		}
+		m.routines = append(m.routines[:at], append([]Routine{habit}, m.routines[at:]...)...)
+		if m.current >= at {
+			m.current++
+		}
+		m.outlineCursor = at
+		m.outlineErr = ""
+		m.outlineInserting = false
+		m.outlineInput.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.outlineInput, cmd = m.outlineInput.Update(msg)
+	return m, cmd
+}
+
+// nextPendingHabit returns the first pending habit after i, wrapping around to
+// the habits before it, or -1 if there is none.
+func (m model) nextPendingHabit(i int) int {
+	for k := 1; k < len(m.routines); k++ {
+		if j := (i + k) % len(m.routines); m.routines[j].Status == habitPending {
+			return j
+		}
+	}
+	return -1
+}
+
+// parseAdHocHabit turns "Call mom 10m" into a habit named "Call mom" lasting ten minutes.
+func parseAdHocHabit(s string) (Routine, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return Routine{}, fmt.Errorf("enter a name and a duration, e.g. \"Call mom 10m\"")
+	}
+	last := fields[len(fields)-1]
+	if _, err := parseDuration(last); err != nil {
+		return Routine{}, fmt.Errorf("enter a name and a duration, e.g. \"Call mom 10m\"")
+	}
+	return Routine{Title: strings.Join(fields[:len(fields)-1], " "), Time: last}, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Context tags that decide where a quote is shown. Besides these, a quote
+// tagged with a routine or habit name (e.g. #morning-productivity) is shown
+// while that routine or habit runs.
+const (
+	focusTag = "focus" // While a habit runs, when the routine has no quotes of its own
+	restTag  = "rest"  // During breaks between habits
+)
+
+// timeOfDayTags limit a quote to part of the day on the quotes screen.
+var timeOfDayTags = []string{"morning", "afternoon", "evening"}
+
+// timeOfDay names the part of the day, with the same boundaries as greet.
+func timeOfDay(t time.Time) string {
+	switch {
+	case t.Hour() < 12:
+		return "morning"
+	case t.Hour() < 18:
+		return "afternoon"
+	}
+	return "evening"
+}
+
+// fitsTimeOfDay reports whether q may be shown in part of the day tod.
+// Quotes without a time-of-day tag fit any time, and only they fit an empty tod.
+func fitsTimeOfDay(q Quote, tod string) bool {
+	tagged := false
+	for _, tag := range q.Tags {
+		if tag == tod {
+			return true
+		}
+		if slices.Contains(timeOfDayTags, tag) {
+			tagged = true
+		}
+	}
+	return !tagged
+}
+
+// contextTag turns a routine or habit name into the tag that selects its quotes,
+// e.g. "Morning Productivity" becomes "morning-productivity".
+func contextTag(name string) string {
+	tags := parseTags(strings.Join(strings.Fields(strings.ToLower(name)), "-"))
+	if len(tags) == 0 {
+		return ""
+	}
+	return tags[0]
+}
+
+// pickContextQuote returns a quote carrying one of the tags. The pick depends
+// only on key, so the same context shows the same quote on every render.
+func pickContextQuote(quotes []Quote, tags []string, key string) (Quote, bool) {
+	var candidates []Quote
+	for _, q := range quotes {
+		for _, tag := range tags {
+			if tag != "" && slices.Contains(q.Tags, tag) {
+				candidates = append(candidates, q)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Quote{}, false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return candidates[h.Sum32()%uint32(len(candidates))], true
+}
+
+// timeOfDayQuote is the quote shown under the quote of the day, picked from those
+// tagged with the current part of the day. It stays the same until the next part of the day.
+func timeOfDayQuote(quotes []Quote, now time.Time) (Quote, bool) {
+	tod := timeOfDay(now)
+	return pickContextQuote(quotes, []string{tod}, now.Format("2006-01-02")+tod)
+}
+
+// runningQuote is the quote shown above a running habit: one tagged with the
+// routine or the habit, otherwise a focus quote. It changes daily.
+func (m model) runningQuote() (Quote, bool) {
+	r := m.currentRoutine()
+	day := time.Now().Format("2006-01-02")
+	routine := contextTag(routineDisplayName(m.routineFileName))
+	if q, ok := pickContextQuote(m.quotes, []string{routine, contextTag(r.Title)}, day+r.Title); ok {
+		return q, true
+	}
+	return pickContextQuote(m.quotes, []string{focusTag}, day+r.Title)
+}
+
+// breakQuote is the quote shown during a break between habits: a rest quote,
+// otherwise any quote. It changes with each break.
+func (m model) breakQuote() (Quote, bool) {
+	key := m.pauseStart.Format(time.RFC3339)
+	if q, ok := pickContextQuote(m.quotes, []string{restTag}, key); ok {
+		return q, true
+	}
+	if len(m.quotes) == 0 {
+		return Quote{}, false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return m.quotes[h.Sum32()%uint32(len(m.quotes))], true
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// quoteHistoryPath records when each quote was shown, so quotes don't repeat within a week.
+	quoteHistoryPath = "quotes/history.md"
+	quoteNoRepeat    = 7 * 24 * time.Hour
+	quoteHistoryKeep = 30 * 24 * time.Hour
+	favoriteTag      = "favorite"
+)
+
+// quoteShown is one line of the quote history.
+type quoteShown struct {
+	At   time.Time
+	Text string
+}
+
+// quoteRotateMsg asks for the next quote in rotation mode. gen drops ticks from
+// an earlier visit to the quotes screen.
+type quoteRotateMsg struct {
+	gen int
+}
+
+var quoteHistoryRE = regexp.MustCompile(`^-\s*(\d{4}-\d{2}-\d{2} \d{2}:\d{2}):\s*(.+)$`)
+
+// loadQuoteHistory reads "- 2006-01-02 15:04: quote text" lines.
+func loadQuoteHistory(path string) []quoteShown {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var history []quoteShown
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		matches := quoteHistoryRE.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if matches == nil {
+			continue
+		}
+		at, err := time.ParseInLocation("2006-01-02 15:04", matches[1], time.Local)
+		if err != nil {
+			continue
+		}
+		history = append(history, quoteShown{At: at, Text: matches[2]})
+	}
+	return history
+}
+
+// writeQuoteHistory rewrites the history, keeping the last month.
+func writeQuoteHistory(path string, history []quoteShown, now time.Time) error {
+	var b strings.Builder
+	b.WriteString("# Shown quotes\n")
+	for _, h := range history {
+		if now.Sub(h.At) <= quoteHistoryKeep {
+			b.WriteString(fmt.Sprintf("- %s: %s\n", h.At.Format("2006-01-02 15:04"), h.Text))
+		}
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// quoteKey identifies a quote in the history regardless of renumbering.
+func quoteKey(q Quote) string {
+	return quoteTextKey(q.Text)
+}
+
+// quoteTextKey normalizes quote text for comparison.
+func quoteTextKey(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// isFavorite reports whether the quote carries the favorite tag.
+func isFavorite(q Quote) bool {
+	return slices.Contains(q.Tags, favoriteTag)
+}
+
+// quoteCandidates returns the quotes that may be picked: those that fit the
+// part of the day tod, favorites only when asked and there are any, minus those shown
+// in the week before now. When every quote was shown recently it falls back to all of them.
+// An empty tod keeps only quotes without a time-of-day tag.
+func quoteCandidates(quotes []Quote, history []quoteShown, now time.Time, tod string, favoritesOnly bool) []int {
+	recent := make(map[string]bool)
+	for _, h := range history {
+		if now.Sub(h.At) < quoteNoRepeat && h.At.Before(now) {
+			recent[quoteTextKey(h.Text)] = true
+		}
+	}
+
+	filter := func(keep func(Quote) bool) []int {
+		var pool []int
+		for i, q := range quotes {
+			if keep(q) {
+				pool = append(pool, i)
+			}
+		}
+		return pool
+	}
+	pool := filter(func(q Quote) bool { return fitsTimeOfDay(q, tod) && (!favoritesOnly || isFavorite(q)) })
+	if len(pool) == 0 {
+		pool = filter(func(q Quote) bool { return fitsTimeOfDay(q, tod) })
+	}
+	if len(pool) == 0 {
+		pool = filter(func(Quote) bool { return true })
+	}
+
+	var fresh []int
+	for _, i := range pool {
+		if !recent[quoteKey(quotes[i])] {
+			fresh = append(fresh, i)
+		}
+	}
+	if len(fresh) == 0 {
+		return pool
+	}
+	return fresh
+}
+
+// dailyQuote returns the quote of the day: the first one shown today if it is
+// in the history, otherwise a pick seeded by the date so it is the same all day.
+// Quotes tagged with a part of the day are left to timeOfDayQuote.
+func dailyQuote(quotes []Quote, history []quoteShown, now time.Time, favoritesOnly bool) int {
+	if len(quotes) == 0 {
+		return -1
+	}
+	today := startOfDay(now)
+	for _, h := range history {
+		if sameDay(h.At, today) {
+			for i, q := range quotes {
+				if quoteKey(q) == quoteTextKey(h.Text) && fitsTimeOfDay(q, "") {
+					return i
+				}
+			}
+		}
+	}
+	candidates := quoteCandidates(quotes, history, today, "", favoritesOnly)
+	seed := int64(now.Year()*10000 + int(now.Month())*100 + now.Day())
+	return candidates[rand.New(rand.NewSource(seed)).Intn(len(candidates))]
+}
+
+// currentQuote returns the quote on the quotes screen, or a default when there are none.
+func (m model) currentQuote() Quote {
+	if m.quoteIndex >= 0 && m.quoteIndex < len(m.quotes) {
+		return m.quotes[m.quoteIndex]
+	}
+	return getRandomQuote(nil)
+}
+
+// openQuotes switches to the quotes screen. The quote of the day, or in
+// rotation mode a fresh quote, is picked the first time.
+func (m *model) openQuotes() tea.Cmd {
+	m.state = stateQuotes
+	m.quoteErr = ""
+	if len(m.quoteTrail) == 0 || m.quoteIndex >= len(m.quotes) {
+		if m.config.QuoteMode == "rotate" {
+			m.nextQuote()
+		} else {
+			m.showQuote(dailyQuote(m.quotes, m.quoteHistory, time.Now(), m.config.FavoriteQuotes))
+		}
+	}
+	if m.config.QuoteMode != "rotate" {
+		return nil
+	}
+	m.quoteRotateGen++
+	return m.rotateQuoteCmd()
+}
+
+// rotateQuoteCmd waits for the next rotation.
+func (m model) rotateQuoteCmd() tea.Cmd {
+	gen := m.quoteRotateGen
+	return tea.Tick(m.config.QuoteInterval, func(time.Time) tea.Msg { return quoteRotateMsg{gen: gen} })
+}
+
+// showQuote puts quote i on screen, adds it to the trail for the previous key
+// and records it in the history.
+func (m *model) showQuote(i int) {
+	if i < 0 || i >= len(m.quotes) {
+		return
+	}
+	if len(m.quoteTrail) > 0 {
+		m.quoteTrail = m.quoteTrail[:m.quoteTrailPos+1] // Drop what was ahead after going back
+	}
+	m.quoteTrail = append(m.quoteTrail, i)
+	m.quoteTrailPos = len(m.quoteTrail) - 1
+	m.quoteIndex = i
+
+	now := time.Now()
+	m.quoteHistory = append(m.quoteHistory, quoteShown{At: now, Text: strings.Join(strings.Fields(m.quotes[i].Text), " ")})
+	writeQuoteHistory(quoteHistoryPath, m.quoteHistory, now)
+}
+
+// nextQuote moves forward along the trail, or picks a quote not shown this week.
+func (m *model) nextQuote() {
+	if m.quoteTrailPos < len(m.quoteTrail)-1 {
+		m.quoteTrailPos++
+		m.quoteIndex = m.quoteTrail[m.quoteTrailPos]
+		return
+	}
+	if len(m.quotes) == 0 {
+		return
+	}
+	now := time.Now()
+	candidates := quoteCandidates(m.quotes, m.quoteHistory, now, timeOfDay(now), m.config.FavoriteQuotes)
+	// Don't show the same quote twice in a row when there is a choice.
+	if len(candidates) > 1 {
+		candidates = slices.DeleteFunc(candidates, func(i int) bool { return i == m.quoteIndex })
+	}
+	m.showQuote(candidates[rand.Intn(len(candidates))])
+}
+
+// previousQuote goes back along the trail of quotes shown since timey started.
+func (m *model) previousQuote() {
+	if m.quoteTrailPos > 0 {
+		m.quoteTrailPos--
+		m.quoteIndex = m.quoteTrail[m.quoteTrailPos]
+	}
+}
+
+// forgetQuoteTrail drops the trail after quotes were added, edited or deleted,
+// since its positions no longer match.
+func (m *model) forgetQuoteTrail() {
+	m.quoteTrail = nil
+	m.quoteTrailPos = 0
+}
+
+// toggleFavorite adds or removes the favorite tag on the current quote and saves quotes.md.
+func (m *model) toggleFavorite() {
+	if m.quoteIndex < 0 || m.quoteIndex >= len(m.quotes) {
+		return
+	}
+	quotes := slices.Clone(m.quotes)
+	q := quotes[m.quoteIndex]
+	if isFavorite(q) {
+		q.Tags = slices.DeleteFunc(slices.Clone(q.Tags), func(t string) bool { return t == favoriteTag })
+	} else {
+		q.Tags = append(slices.Clone(q.Tags), favoriteTag)
+	}
+	quotes[m.quoteIndex] = q
+	if err := writeQuotes(quotesPath, quotes); err != nil {
+		m.quoteErr = "Could not save quotes: " + err.Error()
+		return
+	}
+	m.quoteErr = ""
+	m.quotes = quotes
+	m.quoteWarnings = 0
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+var testQuotes = []Quote{
+	{Text: "One", Author: "A"},
+	{Text: "Two", Author: "B"},
+	{Text: "Three", Author: "C"},
+	{Text: "Rise and shine", Author: "D", Tags: []string{"morning"}},
+	{Text: "Wind down", Author: "E", Tags: []string{"evening"}},
+}
+
+func TestDailyQuoteStableAllDay(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	first := dailyQuote(testQuotes, nil, day.Add(8*time.Hour), false)
+	for _, hour := range []int{0, 11, 12, 17, 18, 23} {
+		if got := dailyQuote(testQuotes, nil, day.Add(time.Duration(hour)*time.Hour), false); got != first {
+			t.Errorf("quote of the day at %d:00 is %d, at 8:00 it was %d", hour, got, first)
+		}
+	}
+	if len(testQuotes[first].Tags) > 0 {
+		t.Errorf("quote of the day %+v has a time-of-day tag", testQuotes[first])
+	}
+}
+
+func TestDailyQuoteKeepsFirstShown(t *testing.T) {
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.Local)
+	history := []quoteShown{{At: now.Add(-10 * time.Hour), Text: "Two"}}
+	if got := dailyQuote(testQuotes, history, now, false); got != 1 {
+		t.Errorf("dailyQuote = %d, want the quote already shown today", got)
+	}
+}
+
+func TestQuoteCandidatesSkipRecent(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	history := []quoteShown{
+		{At: now.Add(-24 * time.Hour), Text: "One"},
+		{At: now.Add(-8 * 24 * time.Hour), Text: "Two"}, // Over a week ago
+	}
+	got := quoteCandidates(testQuotes, history, now, "morning", false)
+	want := []int{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("candidates = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestTimeOfDayQuote(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	if q, ok := timeOfDayQuote(testQuotes, day.Add(9*time.Hour)); !ok || q.Text != "Rise and shine" {
+		t.Errorf("morning quote = %+v, %v", q, ok)
+	}
+	if q, ok := timeOfDayQuote(testQuotes, day.Add(14*time.Hour)); ok {
+		t.Errorf("afternoon quote = %+v, want none", q)
+	}
+}
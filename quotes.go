@@ -2,39 +2,188 @@ package main
 
 import (
     "bufio"
+    "fmt"
     "math/rand"
     "os"
     "regexp"
     "strings"
-    "time"
 )
 
-func loadQuotes(path string) ([]Quote, error) {
+// quotesPath is where quotes are read from and written to.
+const quotesPath = "quotes/quotes.md"
+
+var (
+    // 1. "text, with \"escaped\" quotes" - Author, _Source_ #tag #tag
+    // The number, the author and the kind of quote marks and dash are all optional.
+    // The closing quote is the last one that isn't escaped with a backslash.
+    quoteRE       = regexp.MustCompile(`^(?:\d+[.)]|[-*+])?\s*["“”„«](.*[^\\](?:\\\\)*|(?:\\\\)+)["”“»]\s*(.*)$`)
+    quoteDashRE   = regexp.MustCompile(`^(?:--|[-—–~])\s*`)
+    quoteHeadRE   = regexp.MustCompile(`^#+(\s|$)`)
+    blockquoteRE  = regexp.MustCompile(`^>\s?(.*)$`)
+    quoteTagsRE   = regexp.MustCompile(`(?:^|\s+)(?:#[\w-]+\s*)+$`)
+    quoteSourceRE = regexp.MustCompile(`^(.*?),\s*_(.+)_$`)
+)
+
+// unknownAuthor is used for quotes written without an author.
+const unknownAuthor = "Unknown"
+
+// loadQuotes reads quotes.md. Besides the numbered lines the writer produces it
+// accepts curly quotes, any dash before the author, a missing author and
+// markdown blockquotes spanning several lines:
+//
+//	> Time you enjoy wasting
+//	> is not wasted time.
+//	> — Marthe Troly-Curtin
+//
+// It returns how many lines could not be read as a quote.
+func loadQuotes(path string) ([]Quote, int, error) {
     file, err := os.Open(path)
     if err != nil {
-        return nil, err
+        return nil, 0, err
     }
     defer file.Close()
 
     var quotes []Quote
-    scanner := bufio.NewScanner(file)
-    quoteRE := regexp.MustCompile(`^\d+\.\s*"([^"]+)"\s*-\s*(.+)$`)
+    skipped := 0
+    var block []string // Lines of the blockquote being read
+    flush := func(attribution string) {
+        if len(block) > 0 {
+            quotes = append(quotes, newQuote(strings.Trim(strings.Join(block, " "), `"“”„«»`), attribution))
+        }
+        block = nil
+    }
 
+    scanner := bufio.NewScanner(file)
     for scanner.Scan() {
         line := strings.TrimSpace(scanner.Text())
-        if line == "" || strings.HasPrefix(line, "#") {
+        if line == "" || quoteHeadRE.MatchString(line) {
+            flush("")
+            continue
+        }
+
+        if matches := blockquoteRE.FindStringSubmatch(line); matches != nil {
+            content := strings.TrimSpace(matches[1])
+            switch {
+            case content == "":
+            case len(block) > 0 && quoteDashRE.MatchString(content):
+                flush(content)
+            case len(block) == 0 && quoteRE.MatchString(content):
+                // A whole quote on one blockquote line.
+                quotes = append(quotes, parseQuoteLine(content))
+            default:
+                block = append(block, content)
+            }
             continue
         }
 
-        if matches := quoteRE.FindStringSubmatch(line); matches != nil {
-            quotes = append(quotes, Quote{
-                Text:   matches[1],
-                Author: matches[2],
-            })
+        // An attribution right after a blockquote, outside of it.
+        if len(block) > 0 && quoteDashRE.MatchString(line) && !quoteRE.MatchString(line) {
+            flush(line)
+            continue
+        }
+        flush("")
+
+        if quoteRE.MatchString(line) {
+            quotes = append(quotes, parseQuoteLine(line))
+        } else {
+            skipped++
+        }
+    }
+    flush("")
+
+    return quotes, skipped, scanner.Err()
+}
+
+// parseQuoteLine reads a single-line quote matched by quoteRE.
+func parseQuoteLine(line string) Quote {
+    matches := quoteRE.FindStringSubmatch(line)
+    return newQuote(unescapeQuote(matches[1]), matches[2])
+}
+
+// newQuote builds a quote from its text and an attribution like "— Author, _Source_ #tag".
+func newQuote(text, attribution string) Quote {
+    q := Quote{Text: strings.TrimSpace(text)}
+    q.Author, q.Source, q.Tags = parseAttribution(quoteDashRE.ReplaceAllString(strings.TrimSpace(attribution), ""))
+    q.Author, q.Source = unescapeQuote(q.Author), unescapeQuote(q.Source)
+    if q.Author == "" {
+        q.Author = unknownAuthor
+    }
+    return q
+}
+
+// parseAttribution splits `Author, _Source_ #tag #tag` into its parts.
+func parseAttribution(s string) (author, source string, tags []string) {
+    s = strings.TrimSpace(s)
+    if loc := quoteTagsRE.FindStringIndex(s); loc != nil {
+        for _, tag := range strings.Fields(s[loc[0]:]) {
+            tags = append(tags, strings.ToLower(strings.TrimPrefix(tag, "#")))
         }
+        s = strings.TrimSpace(s[:loc[0]])
+    }
+    if matches := quoteSourceRE.FindStringSubmatch(s); matches != nil {
+        return strings.TrimSpace(matches[1]), strings.TrimSpace(matches[2]), tags
+    }
+    return s, "", tags
+}
+
+// escapeQuote makes text safe to put between double quotes in quotes.md.
+func escapeQuote(s string) string {
+    return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s)
+}
+
+// escapeAttribution makes an author or source safe to put after the closing
+// quote: quoteRE would take any closing quote mark in them for the end of the text.
+func escapeAttribution(s string) string {
+    return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `“`, `\“`, `”`, `\”`, `»`, `\»`, "\n", " ").Replace(strings.TrimSpace(s))
+}
+
+// unescapeQuote undoes escapeQuote and escapeAttribution.
+func unescapeQuote(s string) string {
+    return strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\“`, `“`, `\”`, `”`, `\»`, `»`).Replace(s)
+}
+
+// formatQuote renders a quote as line n of quotes.md.
+func formatQuote(n int, q Quote) string {
+    line := fmt.Sprintf("%d. \"%s\" - %s", n, escapeQuote(q.Text), escapeAttribution(q.Author))
+    if q.Source != "" {
+        line += fmt.Sprintf(", _%s_", escapeAttribution(q.Source))
+    }
+    for _, tag := range q.Tags {
+        line += " #" + tag
     }
+    return line + "\n"
+}
 
-    return quotes, scanner.Err()
+// writeQuotes rewrites quotes.md with the quotes numbered from 1. It refuses
+// while the file has lines loadQuotes can't read, since they would be lost.
+func writeQuotes(path string, quotes []Quote) error {
+    if _, skipped, err := loadQuotes(path); err == nil && skipped > 0 {
+        return fmt.Errorf("%s has %d lines that aren't quotes, fix or remove them first", path, skipped)
+    }
+    var b strings.Builder
+    b.WriteString("# Quotes\n")
+    for i, q := range quotes {
+        b.WriteString(formatQuote(i+1, q))
+    }
+    return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// parseTags reads a comma or space separated tag list as typed in the quotes manager.
+func parseTags(s string) []string {
+    var tags []string
+    for _, tag := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+        tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+        tag = strings.Map(func(r rune) rune {
+            if r == '-' || r == '_' || ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+                return r
+            }
+            return -1
+        }, tag)
+        if tag != "" {
+            tags = append(tags, tag)
+        }
+    }
+    return tags
 }
 
 func getRandomQuote(quotes []Quote) Quote {
@@ -44,7 +193,6 @@ func getRandomQuote(quotes []Quote) Quote {
             Author: "William Penn",
         }
     }
-    
-    rand.Seed(time.Now().UnixNano())
+
     return quotes[rand.Intn(len(quotes))]
-}
\ No newline at end of file
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// runQuotes implements `timey quotes <subcommand>`.
+func runQuotes(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: timey quotes import [-format csv|json|fortune] FILE...")
+	}
+	switch args[0] {
+	case "import":
+		return runQuotesImport(args[1:])
+	default:
+		return fmt.Errorf("unknown quotes command %q", args[0])
+	}
+}
+
+// runQuotesImport reads quote collections and appends the quotes that aren't
+// in quotes.md yet, numbered after the existing ones.
+func runQuotesImport(args []string) error {
+	fs := flag.NewFlagSet("quotes import", flag.ContinueOnError)
+	format := fs.String("format", "", "csv, json or fortune (default: from the file extension)")
+	out := fs.String("to", quotesPath, "quotes file to append to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("no files to import")
+	}
+
+	existing, _, err := loadQuotes(*out)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, q := range existing {
+		seen[quoteKey(q)] = true
+	}
+
+	var added []Quote
+	duplicates := 0
+	for _, path := range fs.Args() {
+		quotes, err := readQuoteCollection(path, *format)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, q := range quotes {
+			if seen[quoteKey(q)] {
+				duplicates++
+				continue
+			}
+			seen[quoteKey(q)] = true
+			added = append(added, q)
+		}
+	}
+
+	if err := appendQuotes(*out, len(existing), added); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d quotes into %s, skipped %d duplicates\n", len(added), *out, duplicates)
+	return nil
+}
+
+// readQuoteCollection reads one file in the given format, or the one its extension suggests.
+func readQuoteCollection(path, format string) ([]Quote, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".json":
+			format = "json"
+		default:
+			format = "fortune"
+		}
+	}
+
+	var quotes []Quote
+	switch format {
+	case "csv":
+		quotes, err = parseQuotesCSV(bytes.NewReader(data))
+	case "json":
+		quotes, err = parseQuotesJSON(data)
+	case "fortune":
+		quotes = parseQuotesFortune(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop empty quotes and fill in what the writer expects.
+	var clean []Quote
+	for _, q := range quotes {
+		q.Text = strings.Join(strings.Fields(q.Text), " ")
+		q.Author = strings.TrimSpace(q.Author)
+		q.Source = strings.TrimSpace(q.Source)
+		if q.Text == "" {
+			continue
+		}
+		if q.Author == "" {
+			q.Author = unknownAuthor
+		}
+		clean = append(clean, q)
+	}
+	return clean, nil
+}
+
+// parseQuotesCSV reads text, author, source and tags columns. A header row
+// naming the columns may put them in any order; without one they are in that order.
+func parseQuotesCSV(r io.Reader) ([]Quote, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	cols := map[string]int{"text": 0, "author": 1, "source": 2, "tags": 3}
+	header := make(map[string]int)
+	for i, name := range rows[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "text", "quote", "content":
+			header["text"] = i
+		case "author", "by":
+			header["author"] = i
+		case "source":
+			header["source"] = i
+		case "tags", "tag", "category":
+			header["tags"] = i
+		}
+	}
+	if _, ok := header["text"]; ok {
+		cols = header
+		rows = rows[1:]
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	var quotes []Quote
+	for _, row := range rows {
+		quotes = append(quotes, Quote{
+			Text:   strings.Trim(field(row, "text"), `"“”`),
+			Author: field(row, "author"),
+			Source: field(row, "source"),
+			Tags:   parseTags(field(row, "tags")),
+		})
+	}
+	return quotes, nil
+}
+
+// jsonQuote accepts the field names common in quote collections.
+type jsonQuote struct {
+	Text    string          `json:"text"`
+	Quote   string          `json:"quote"`
+	Content string          `json:"content"`
+	Author  string          `json:"author"`
+	By      string          `json:"by"`
+	Source  string          `json:"source"`
+	Tags    json.RawMessage `json:"tags"`
+}
+
+// parseQuotesJSON reads an array of quote objects, or of plain strings.
+func parseQuotesJSON(data []byte) ([]Quote, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("expected a JSON array: %w", err)
+	}
+
+	var quotes []Quote
+	for _, item := range items {
+		var text string
+		if json.Unmarshal(item, &text) == nil {
+			quotes = append(quotes, Quote{Text: text})
+			continue
+		}
+		var jq jsonQuote
+		if err := json.Unmarshal(item, &jq); err != nil {
+			return nil, err
+		}
+		q := Quote{
+			Text:   firstNonEmpty(jq.Text, jq.Quote, jq.Content),
+			Author: firstNonEmpty(jq.Author, jq.By),
+			Source: jq.Source,
+		}
+		// Tags may be a list or a comma separated string.
+		var tags []string
+		var tagString string
+		if json.Unmarshal(jq.Tags, &tags) == nil {
+			q.Tags = parseTags(strings.Join(tags, ","))
+		} else if json.Unmarshal(jq.Tags, &tagString) == nil {
+			q.Tags = parseTags(tagString)
+		}
+		quotes = append(quotes, q)
+	}
+	return quotes, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// fortuneAuthorRE matches the attribution line of a fortune, e.g. "		-- Mark Twain".
+var fortuneAuthorRE = regexp.MustCompile(`^\s*(?:--|[—–~])\s*(.+)$`)
+
+// parseQuotesFortune reads fortune files: quotes separated by lines holding
+// only "%", each optionally ending in an attribution line.
+func parseQuotesFortune(r io.Reader) []Quote {
+	var quotes []Quote
+	var lines []string
+	author := ""
+	flush := func() {
+		if len(lines) > 0 {
+			quotes = append(quotes, Quote{
+				Text:   strings.Trim(strings.Join(lines, " "), `"“” `),
+				Author: author,
+			})
+		}
+		lines, author = nil, ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "%" {
+			flush()
+			continue
+		}
+		if match := fortuneAuthorRE.FindStringSubmatch(line); match != nil && len(lines) > 0 {
+			author = match[1]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	flush()
+	return quotes
+}
+
+// appendQuotes adds quotes to the end of path, numbered after the existing count.
+func appendQuotes(path string, existing int, quotes []Quote) error {
+	if len(quotes) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	current, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		b.WriteString("# Quotes\n")
+	case err != nil:
+		return err
+	case len(current) > 0 && !bytes.HasSuffix(current, []byte("\n")):
+		b.WriteString("\n")
+	}
+	for i, q := range quotes {
+		b.WriteString(formatQuote(existing+i+1, q))
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(b.String())
+	return err
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseQuotesCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Quote
+	}{
+		{"no header", "Stay hungry,Steve Jobs,Stanford,focus\n",
+			[]Quote{{Text: "Stay hungry", Author: "Steve Jobs", Source: "Stanford", Tags: []string{"focus"}}}},
+		{"header in another order", "author,quote,category\nSeneca,\"Luck is preparation.\",\"morning, work\"\n",
+			[]Quote{{Text: "Luck is preparation.", Author: "Seneca", Tags: []string{"morning", "work"}}}},
+		{"short rows", "text,author\nAlone\n",
+			[]Quote{{Text: "Alone"}}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseQuotesCSV(strings.NewReader(tt.in))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuotesJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []Quote
+		wantErr bool
+	}{
+		{"objects", `[{"quote": "Less is more.", "by": "Mies", "tags": ["design"]}, {"content": "Carpe diem", "author": "Horace", "tags": "latin, Evening"}]`,
+			[]Quote{
+				{Text: "Less is more.", Author: "Mies", Tags: []string{"design"}},
+				{Text: "Carpe diem", Author: "Horace", Tags: []string{"latin", "evening"}},
+			}, false},
+		{"strings", `["Just one", "Another"]`,
+			[]Quote{{Text: "Just one"}, {Text: "Another"}}, false},
+		{"not an array", `{"text": "Lonely"}`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseQuotesJSON([]byte(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuotesFortune(t *testing.T) {
+	in := "The best way out\nis always through.\n\t\t-- Robert Frost\n%\n\"No author here.\"\n%\n%\n-- leading dashes stay text\n"
+	want := []Quote{
+		{Text: "The best way out is always through.", Author: "Robert Frost"},
+		{Text: "No author here."},
+		{Text: "-- leading dashes stay text"},
+	}
+	if got := parseQuotesFortune(strings.NewReader(in)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadQuoteCollectionCleans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	data := `[{"text": "  Spaced   out\n text ", "author": " "}, {"text": "   "}]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readQuoteCollection(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Quote{{Text: "Spaced out text", Author: unknownAuthor}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
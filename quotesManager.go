@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fields of the quote form, in tab order.
+const (
+	quoteFieldText = iota
+	quoteFieldAuthor
+	quoteFieldSource
+	quoteFieldTags
+	quoteFieldCount
+)
+
+// newQuoteInputs creates the text inputs of the quote form.
+func newQuoteInputs() []textinput.Model {
+	labels := []string{"Quote:", "Author:", "Source:", "Tags:"}
+	inputs := make([]textinput.Model, quoteFieldCount)
+	for i := range inputs {
+		ti := textinput.New()
+		ti.Placeholder = labels[i]
+		ti.Prompt = fmt.Sprintf("%-8s ", labels[i])
+		ti.PromptStyle = blurredStyle
+		ti.Cursor.Style = focusedStyle
+		ti.CharLimit = 500
+		inputs[i] = ti
+	}
+	inputs[quoteFieldSource].Placeholder = "book, talk or link (optional)"
+	inputs[quoteFieldTags].Placeholder = "morning, focus (optional)"
+	return inputs
+}
+
+// openQuotesManager shows the list of quotes.
+func (m *model) openQuotesManager() {
+	m.state = stateQuotesManager
+	m.quoteEditing = false
+	m.quoteConfirmDelete = false
+	m.quoteErr = ""
+	m.quoteCursor = min(m.quoteCursor, max(len(m.quotes)-1, 0))
+}
+
+// editQuote opens the form on quote i, or on a new quote when i is -1.
+func (m *model) editQuote(i int) tea.Cmd {
+	var q Quote
+	if i >= 0 && i < len(m.quotes) {
+		q = m.quotes[i]
+	}
+	values := []string{q.Text, q.Author, q.Source, strings.Join(q.Tags, ", ")}
+	for f := range m.quoteInputs {
+		m.quoteInputs[f].SetValue(values[f])
+	}
+	m.quoteEditIndex = i
+	m.quoteEditing = true
+	m.quoteErr = ""
+	return m.focusQuoteField(quoteFieldText)
+}
+
+// focusQuoteField moves the form's focus to field f.
+func (m *model) focusQuoteField(f int) tea.Cmd {
+	m.quoteField = f
+	for i := range m.quoteInputs {
+		if i == f {
+			m.quoteInputs[i].Focus()
+			m.quoteInputs[i].PromptStyle = focusedStyle
+		} else {
+			m.quoteInputs[i].Blur()
+			m.quoteInputs[i].PromptStyle = blurredStyle
+		}
+	}
+	return textinput.Blink
+}
+
+// saveEditedQuote stores the form and rewrites quotes.md.
+func (m *model) saveEditedQuote() {
+	q := Quote{
+		Text:   strings.TrimSpace(m.quoteInputs[quoteFieldText].Value()),
+		Author: strings.TrimSpace(m.quoteInputs[quoteFieldAuthor].Value()),
+		Source: strings.Trim(strings.TrimSpace(m.quoteInputs[quoteFieldSource].Value()), "_"),
+		Tags:   parseTags(m.quoteInputs[quoteFieldTags].Value()),
+	}
+	if q.Text == "" {
+		m.quoteErr = "The quote can't be empty."
+		m.focusQuoteField(quoteFieldText)
+		return
+	}
+	if q.Author == "" {
+		q.Author = "Unknown"
+	}
+
+	quotes := append([]Quote(nil), m.quotes...)
+	if m.quoteEditIndex >= 0 && m.quoteEditIndex < len(quotes) {
+		quotes[m.quoteEditIndex] = q
+	} else {
+		quotes = append(quotes, q)
+		m.quoteEditIndex = len(quotes) - 1
+	}
+	if err := writeQuotes(quotesPath, quotes); err != nil {
+		m.quoteErr = "Could not save quotes: " + err.Error()
+		return
+	}
+	m.quotes = quotes
+	m.quoteWarnings = 0
+	m.forgetQuoteTrail()
+	m.quoteCursor = m.quoteEditIndex
+	m.quoteEditing = false
+}
+
+// deleteQuote removes the selected quote and rewrites quotes.md.
+func (m *model) deleteQuote() {
+	if m.quoteCursor >= len(m.quotes) {
+		return
+	}
+	quotes := append(append([]Quote(nil), m.quotes[:m.quoteCursor]...), m.quotes[m.quoteCursor+1:]...)
+	if err := writeQuotes(quotesPath, quotes); err != nil {
+		m.quoteErr = "Could not save quotes: " + err.Error()
+		return
+	}
+	m.quotes = quotes
+	m.quoteWarnings = 0
+	m.forgetQuoteTrail()
+	m.quoteCursor = min(m.quoteCursor, max(len(m.quotes)-1, 0))
+}
+
+// updateQuotesManager handles keys for the quotes list and form. It takes every key
+// so that letters can be typed into the form.
+func (m *model) updateQuotesManager(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.quoteEditing {
+		switch msg.String() {
+		case "esc":
+			m.quoteEditing = false
+			m.quoteErr = ""
+			return m, nil
+		case "tab", "down":
+			return m, m.focusQuoteField((m.quoteField + 1) % quoteFieldCount)
+		case "shift+tab", "up":
+			return m, m.focusQuoteField((m.quoteField + quoteFieldCount - 1) % quoteFieldCount)
+		case "enter":
+			if m.quoteField < quoteFieldCount-1 {
+				return m, m.focusQuoteField(m.quoteField + 1)
+			}
+			m.saveEditedQuote()
+			return m, nil
+		case "ctrl+s":
+			m.saveEditedQuote()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.quoteInputs[m.quoteField], cmd = m.quoteInputs[m.quoteField].Update(msg)
+		return m, cmd
+	}
+
+	if m.quoteConfirmDelete {
+		if msg.String() == "y" {
+			m.deleteQuote()
+		}
+		m.quoteConfirmDelete = false
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		m.quoteErr = ""
+		return m, m.openQuotes()
+	case "up", "k":
+		if m.quoteCursor > 0 {
+			m.quoteCursor--
+		}
+	case "down", "j":
+		if m.quoteCursor < len(m.quotes)-1 {
+			m.quoteCursor++
+		}
+	case "a":
+		return m, m.editQuote(-1)
+	case "e", "enter":
+		if len(m.quotes) > 0 {
+			return m, m.editQuote(m.quoteCursor)
+		}
+	case "d":
+		if len(m.quotes) > 0 {
+			m.quoteConfirmDelete = true
+		}
+	}
+	return m, nil
+}
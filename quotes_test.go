@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTestQuotes(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "quotes.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadQuotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Quote
+		skipped int
+	}{
+		{"numbered", `1. "Be here now." - Ram Dass`,
+			[]Quote{{Text: "Be here now.", Author: "Ram Dass"}}, 0},
+		{"curly quotes and em dash", `“Simplicity is the ultimate sophistication.” — Leonardo da Vinci`,
+			[]Quote{{Text: "Simplicity is the ultimate sophistication.", Author: "Leonardo da Vinci"}}, 0},
+		{"missing author", `- "Done is better than perfect."`,
+			[]Quote{{Text: "Done is better than perfect.", Author: unknownAuthor}}, 0},
+		{"source and tags", `2. "Stay hungry." - Steve Jobs, _Stanford speech_ #morning #focus`,
+			[]Quote{{Text: "Stay hungry.", Author: "Steve Jobs", Source: "Stanford speech", Tags: []string{"morning", "focus"}}}, 0},
+		{"escaped quotes", `3. "He said \"go\"." - Anon`,
+			[]Quote{{Text: `He said "go".`, Author: "Anon"}}, 0},
+		{"blockquote", "> Time you enjoy wasting\n> is not wasted time.\n> — Marthe Troly-Curtin",
+			[]Quote{{Text: "Time you enjoy wasting is not wasted time.", Author: "Marthe Troly-Curtin"}}, 0},
+		{"notes are skipped", "# Quotes\r\nSome note\r\n1. \"Keep going.\" - Anon\r\n",
+			[]Quote{{Text: "Keep going.", Author: "Anon"}}, 1},
+	}
+	for _, tt := range tests {
+		quotes, skipped, err := loadQuotes(writeTestQuotes(t, tt.content))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(quotes, tt.want) || skipped != tt.skipped {
+			t.Errorf("%s: got %+v skipped %d, want %+v skipped %d", tt.name, quotes, skipped, tt.want, tt.skipped)
+		}
+	}
+}
+
+func TestWriteQuotesRoundTrip(t *testing.T) {
+	path := writeTestQuotes(t, "# Quotes\n")
+	quotes := []Quote{
+		{Text: `Say "hi"`, Author: "Anon", Source: "A book", Tags: []string{"favorite"}},
+		{Text: "Keep going.", Author: unknownAuthor},
+		{Text: "Hi", Author: `Dwayne "The Rock" Johnson`, Source: `The “Best” of \ Me`},
+		{Text: `Ends in a backslash \`, Author: "Anon"},
+	}
+	if err := writeQuotes(path, quotes); err != nil {
+		t.Fatal(err)
+	}
+	got, skipped, err := loadQuotes(path)
+	if err != nil || skipped != 0 || !reflect.DeepEqual(got, quotes) {
+		t.Errorf("read back %+v (skipped %d, %v), want %+v", got, skipped, err, quotes)
+	}
+}
+
+func TestWriteQuotesKeepsUnreadableLines(t *testing.T) {
+	content := "# Quotes\nA note I wrote to myself\n1. \"Keep going.\" - Anon\n"
+	path := writeTestQuotes(t, content)
+	if err := writeQuotes(path, []Quote{{Text: "New", Author: "Me"}}); err == nil {
+		t.Error("writeQuotes rewrote a file with lines it can't read")
+	}
+	got, _ := os.ReadFile(path)
+	if !strings.Contains(string(got), "A note I wrote to myself") {
+		t.Errorf("the note was lost:\n%s", got)
+	}
+}
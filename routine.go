@@ -1,9 +1,7 @@
 package main
 
 import (
-    "bufio"
     "fmt"
-    "os"
     "regexp"
     "strings"
     "time"
@@ -13,23 +11,27 @@ import (
 
 
 // loadRoutines loads routines from a markdown file at a given path.
+// Includes and template variables are resolved before parsing.
 func loadRoutines(path string) ([]Routine, error) {
-	file, err := os.Open(path)
+	lines, err := expandRoutineFile(path, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var routines []Routine
-	scanner := bufio.NewScanner(file)
 
 	var currentRoutine *Routine
 	routineTitleRE := regexp.MustCompile(`^\d+\.\s+(.*)$`)
 	timeRE := regexp.MustCompile(`^- Time:\s*(.*)$`)
+	atRE := regexp.MustCompile(`^- At:\s*(\d{1,2}:\d{2})$`)
 	todoRE := regexp.MustCompile(`^-\s*\[([ x])\]\s*(.*)$`)
+	budgetRE := regexp.MustCompile(`^(.*?)\s*\((\d+\s*(?i:s|secs?|seconds?|m|mins?|minutes?|h|hrs?|hours?))\)$`)
+	optionalRE := regexp.MustCompile(`^- Optional(?::\s*(.*))?$`)
+	daysRE := regexp.MustCompile(`^- Days:\s*(.*)$`)
+	noteRE := regexp.MustCompile(`^(?:- Note:|>)\s*(.*)$`)
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
 
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
@@ -43,13 +45,34 @@ func loadRoutines(path string) ([]Routine, error) {
 			currentRoutine = &Routine{Title: title}
 		} else if timeRE.MatchString(line) && currentRoutine != nil {
 			currentRoutine.Time = timeRE.FindStringSubmatch(line)[1]
+		} else if atRE.MatchString(line) && currentRoutine != nil {
+			currentRoutine.At = atRE.FindStringSubmatch(line)[1]
+		} else if optionalRE.MatchString(line) && currentRoutine != nil {
+			value := optionalRE.FindStringSubmatch(line)[1]
+			currentRoutine.Optional = value == "" || parseBool(value)
+		} else if daysRE.MatchString(line) && currentRoutine != nil {
+			days, err := parseWeekdays(daysRE.FindStringSubmatch(line)[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s: habit %q: %w", path, currentRoutine.Title, err)
+			}
+			currentRoutine.Days = days
+		} else if noteRE.MatchString(line) && currentRoutine != nil {
+			currentRoutine.Notes = append(currentRoutine.Notes, noteRE.FindStringSubmatch(line)[1])
 		} else if todoRE.MatchString(line) && currentRoutine != nil {
 			matches := todoRE.FindStringSubmatch(line)
 			checked := matches[1] == "x"
 			itemText := matches[2]
+			var budget time.Duration
+			if b := budgetRE.FindStringSubmatch(itemText); b != nil {
+				if d, err := parseDuration(b[2]); err == nil {
+					itemText, budget = b[1], d
+				}
+			}
 			currentRoutine.Checklist = append(currentRoutine.Checklist, ChecklistItem{
 				Text:     itemText,
 				Complete: checked,
+				Depth:    checklistDepth(raw, currentRoutine.Checklist),
+				Budget:   budget,
 			})
 		}
 	}
@@ -58,12 +81,56 @@ func loadRoutines(path string) ([]Routine, error) {
 		routines = append(routines, *currentRoutine)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
 	return routines, nil
 }
 
+// routineDisplayName turns a routine file name like "Morning_Productivity.md" into "Morning Productivity".
+func routineDisplayName(fileName string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(fileName, ".md"), "_", " ")
+}
+
+// runsOn reports whether the habit is part of the routine on the given weekday.
+func (r Routine) runsOn(d time.Weekday) bool {
+	return len(r.Days) == 0 || r.Days[d]
+}
+
+// habitsForDay keeps only the habits that run on the given day, so one
+// routine file can serve both weekdays and weekends.
+func habitsForDay(routines []Routine, day time.Time) []Routine {
+	var out []Routine
+	for _, r := range routines {
+		if r.runsOn(day.Weekday()) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// checklistDepth works out how deeply a todo line is nested from its indentation.
+// Every two spaces (or one tab) is a level, but an item is never more than one
+// level deeper than the item before it.
+func checklistDepth(line string, previous []ChecklistItem) int {
+	indent := 0
+	for _, r := range line {
+		if r == ' ' {
+			indent++
+		} else if r == '\t' {
+			indent += 2
+		} else {
+			break
+		}
+	}
+	depth := indent / 2
+	maxDepth := 0
+	if len(previous) > 0 {
+		maxDepth = previous[len(previous)-1].Depth + 1
+	}
+	if depth > maxDepth {
+		depth = maxDepth
+	}
+	return depth
+}
+
 // parseDuration parses a duration string like "5min" or "1h".
 func parseDuration(s string) (time.Duration, error) {
     // First extract any numbers from the string
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadRoutinesChecklistBudgets(t *testing.T) {
+	tests := []struct {
+		line   string
+		text   string
+		budget time.Duration
+	}{
+		{"- [ ] Stretch (5m)", "Stretch", 5 * time.Minute},
+		{"- [ ] Plank (30 s)", "Plank", 30 * time.Second},
+		{"- [ ] Tea (10 min)", "Tea", 10 * time.Minute},
+		{"- [ ] Walk (1h)", "Walk", time.Hour},
+		{"- [ ] Push-ups (20 reps)", "Push-ups (20 reps)", 0},
+		{"- [ ] Read (2 pages)", "Read (2 pages)", 0},
+		{"- [ ] Water plants (kitchen)", "Water plants (kitchen)", 0},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "routine.md")
+		content := "1. Morning\n- Time: 10m\n" + tt.line + "\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		routines, err := loadRoutines(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(routines) != 1 || len(routines[0].Checklist) != 1 {
+			t.Fatalf("%q: got %+v", tt.line, routines)
+		}
+		item := routines[0].Checklist[0]
+		if item.Text != tt.text || item.Budget != tt.budget {
+			t.Errorf("%q: text %q budget %v, want %q %v", tt.line, item.Text, item.Budget, tt.text, tt.budget)
+		}
+	}
+}
+
+func TestHabitsForDay(t *testing.T) {
+	routines := []Routine{
+		{Title: "Every day"},
+		{Title: "Weekdays", Days: map[time.Weekday]bool{time.Monday: true, time.Friday: true}},
+		{Title: "Weekends", Days: map[time.Weekday]bool{time.Saturday: true, time.Sunday: true}},
+	}
+	monday := time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)
+	got := habitsForDay(routines, monday)
+	if len(got) != 2 || got[0].Title != "Every day" || got[1].Title != "Weekdays" {
+		t.Errorf("habitsForDay(Monday) = %+v", got)
+	}
+	if got := habitsForDay(routines[2:], monday); len(got) != 0 {
+		t.Errorf("habitsForDay(Monday) of weekend habits = %+v, want none", got)
+	}
+}
+
+func TestChecklistDepth(t *testing.T) {
+	tests := []struct {
+		line     string
+		previous []ChecklistItem
+		want     int
+	}{
+		{"- [ ] Top", nil, 0},
+		{"  - [ ] Indented first item", nil, 0},
+		{"  - [ ] Child", []ChecklistItem{{Depth: 0}}, 1},
+		{"\t- [ ] Tab child", []ChecklistItem{{Depth: 0}}, 1},
+		{"      - [ ] Too deep", []ChecklistItem{{Depth: 0}}, 1},
+		{"    - [ ] Grandchild", []ChecklistItem{{Depth: 0}, {Depth: 1}}, 2},
+		{"   - [ ] Odd indent", []ChecklistItem{{Depth: 0}, {Depth: 1}}, 1},
+		{"- [ ] Back to top", []ChecklistItem{{Depth: 0}, {Depth: 1}, {Depth: 2}}, 0},
+	}
+	for _, tt := range tests {
+		if got := checklistDepth(tt.line, tt.previous); got != tt.want {
+			t.Errorf("checklistDepth(%q) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scheduleGrace is how late a scheduled routine may still be offered after its start time.
+const scheduleGrace = 15 * time.Minute
+
+// RoutineSchedule describes when a routine file should run, e.g. "weekdays 07:00".
+type RoutineSchedule struct {
+	Days map[time.Weekday]bool // Empty means every day
+	At   string                // Time of day, "15:04"
+}
+
+// scheduleTickMsg checks the schedules, on every screen and at the start of each minute.
+type scheduleTickMsg time.Time
+
+func scheduleTick() tea.Cmd {
+	return tea.Every(time.Minute, func(t time.Time) tea.Msg { return scheduleTickMsg(t) })
+}
+
+// scheduledRoutine is a routine file that carries a schedule.
+type scheduledRoutine struct {
+	FileName    string
+	DisplayName string
+	Schedule    RoutineSchedule
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWeekdays parses "weekdays", "weekends", "daily" or a list like "Mon,Wed".
+// An empty map means every day.
+func parseWeekdays(s string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "daily", "everyday", "every day":
+		return days, nil
+	case "weekdays":
+		for d := time.Monday; d <= time.Friday; d++ {
+			days[d] = true
+		}
+		return days, nil
+	case "weekends":
+		days[time.Saturday] = true
+		days[time.Sunday] = true
+		return days, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if len(name) < 3 {
+			return nil, fmt.Errorf("unknown day: %q", part)
+		}
+		d, ok := weekdayNames[name[:3]]
+		if !ok {
+			return nil, fmt.Errorf("unknown day: %q", part)
+		}
+		days[d] = true
+	}
+	return days, nil
+}
+
+// parseSchedule parses a schedule such as "weekdays 07:00", "Mon,Wed 18:30" or "07:00".
+func parseSchedule(s string) (RoutineSchedule, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return RoutineSchedule{}, fmt.Errorf("empty schedule")
+	}
+	at := fields[len(fields)-1]
+	if _, err := time.Parse("15:04", at); err != nil {
+		return RoutineSchedule{}, fmt.Errorf("schedule needs a time of day like 07:00: %q", s)
+	}
+	days, err := parseWeekdays(strings.Join(fields[:len(fields)-1], " "))
+	if err != nil {
+		return RoutineSchedule{}, err
+	}
+	return RoutineSchedule{Days: days, At: at}, nil
+}
+
+// runsOn reports whether the schedule includes the given weekday.
+func (s RoutineSchedule) runsOn(d time.Weekday) bool {
+	return len(s.Days) == 0 || s.Days[d]
+}
+
+// nextRun returns the first scheduled start at or after now. It expands the
+// schedule as a daily repeating event and skips days the schedule excludes.
+func (s RoutineSchedule) nextRun(now time.Time) time.Time {
+	start, ok := atTime(now, s.At)
+	if !ok {
+		return time.Time{}
+	}
+	next := getNextOccurrence(Event{DateTime: start, Repeat: "daily"}, now)
+	for i := 0; i < 7 && !s.runsOn(next.Weekday()); i++ {
+		next, _ = nextRepeat(next, "daily")
+	}
+	return next
+}
+
+// loadSchedule reads the `Schedule:` line of a routine file, if any.
+func loadSchedule(path string) (RoutineSchedule, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return RoutineSchedule{}, false, err
+	}
+	defer file.Close()
+
+	scheduleRE := regexp.MustCompile(`^(?:-\s*)?Schedule:\s*(.+)$`)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if match := scheduleRE.FindStringSubmatch(line); match != nil {
+			s, err := parseSchedule(match[1])
+			if err != nil {
+				return RoutineSchedule{}, false, err
+			}
+			return s, true, nil
+		}
+	}
+	return RoutineSchedule{}, false, scanner.Err()
+}
+
+// loadRoutineSchedules returns every routine file in dir that carries a valid schedule.
+func loadRoutineSchedules(dir string) []scheduledRoutine {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var out []scheduledRoutine
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		s, ok, err := loadSchedule(filepath.Join(dir, file.Name()))
+		if err != nil || !ok {
+			continue
+		}
+		out = append(out, scheduledRoutine{
+			FileName:    file.Name(),
+			DisplayName: routineDisplayName(file.Name()),
+			Schedule:    s,
+		})
+	}
+	return out
+}
+
+// nextScheduledRoutine returns the routine that is scheduled to start soonest after now.
+func nextScheduledRoutine(schedules []scheduledRoutine, now time.Time) (scheduledRoutine, time.Time, bool) {
+	if len(schedules) == 0 {
+		return scheduledRoutine{}, time.Time{}, false
+	}
+	sorted := make([]scheduledRoutine, len(schedules))
+	copy(sorted, schedules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Schedule.nextRun(now).Before(sorted[j].Schedule.nextRun(now))
+	})
+	return sorted[0], sorted[0].Schedule.nextRun(now), true
+}
+
+// dueScheduledRoutine returns a routine whose start time passed since the last check.
+func dueScheduledRoutine(schedules []scheduledRoutine, lastCheck, now time.Time) (scheduledRoutine, bool) {
+	for _, s := range schedules {
+		run := s.Schedule.nextRun(lastCheck)
+		if !run.After(now) && now.Sub(run) <= scheduleGrace {
+			return s, true
+		}
+	}
+	return scheduledRoutine{}, false
+}
+
+// notify shows a desktop notification when a notifier is available. Errors are ignored,
+// the in-app prompt is always shown anyway.
+func notify(title, body string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", body, title))
+	case "linux":
+		cmd = exec.Command("notify-send", title, body)
+	default:
+		return
+	}
+	go func() { _ = cmd.Run() }()
+}
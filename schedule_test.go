@@ -0,0 +1,120 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		in      string
+		at      string
+		days    []time.Weekday
+		wantErr bool
+	}{
+		{in: "07:00", at: "07:00"},
+		{in: "daily 06:15", at: "06:15"},
+		{in: "weekdays 07:00", at: "07:00", days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{in: "weekends 09:30", at: "09:30", days: []time.Weekday{time.Saturday, time.Sunday}},
+		{in: "Mon, Wednesday 18:30", at: "18:30", days: []time.Weekday{time.Monday, time.Wednesday}},
+		{in: "", wantErr: true},
+		{in: "weekdays", wantErr: true},
+		{in: "25:00", wantErr: true},
+		{in: "Funday 07:00", wantErr: true},
+		{in: "M 07:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSchedule(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSchedule(%q) = %+v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSchedule(%q): %v", tt.in, err)
+			continue
+		}
+		if got.At != tt.at || len(got.Days) != len(tt.days) {
+			t.Errorf("parseSchedule(%q) = %+v, want %s on %v", tt.in, got, tt.at, tt.days)
+			continue
+		}
+		for _, d := range tt.days {
+			if !got.Days[d] {
+				t.Errorf("parseSchedule(%q) = %+v, missing %s", tt.in, got, d)
+			}
+		}
+	}
+}
+
+func TestScheduleNextRun(t *testing.T) {
+	weekdays, err := parseSchedule("weekdays 07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	friday := time.Date(2026, 10, 23, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		now, want time.Time
+	}{
+		{friday.Add(6 * time.Hour), friday.Add(7 * time.Hour)},
+		{friday.Add(7 * time.Hour), friday.Add(7 * time.Hour)},
+		{friday.Add(8 * time.Hour), friday.AddDate(0, 0, 3).Add(7 * time.Hour)},
+	}
+	for _, tt := range tests {
+		if got := weekdays.nextRun(tt.now); !got.Equal(tt.want) {
+			t.Errorf("nextRun(%s) = %s, want %s", tt.now.Format("Mon 15:04"), got.Format("Mon 2 Jan 15:04"), tt.want.Format("Mon 2 Jan 15:04"))
+		}
+	}
+}
+
+func TestDueScheduledRoutine(t *testing.T) {
+	s, _ := parseSchedule("07:00")
+	schedules := []scheduledRoutine{{FileName: "morning.md", Schedule: s}}
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	if _, ok := dueScheduledRoutine(schedules, day.Add(6*time.Hour+59*time.Minute), day.Add(7*time.Hour+time.Minute)); !ok {
+		t.Error("routine not due right after its start time")
+	}
+	if _, ok := dueScheduledRoutine(schedules, day.Add(6*time.Hour), day.Add(8*time.Hour)); ok {
+		t.Error("routine still due an hour after its start time")
+	}
+	if _, ok := dueScheduledRoutine(schedules, day.Add(5*time.Hour), day.Add(6*time.Hour)); ok {
+		t.Error("routine due before its start time")
+	}
+}
+
+func TestScheduledRoutineDueOnAnotherScreen(t *testing.T) {
+	s, _ := parseSchedule("07:00")
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	m := model{
+		state:             stateCalendar,
+		schedules:         []scheduledRoutine{{FileName: "morning.md", DisplayName: "morning", Schedule: s}},
+		lastScheduleCheck: day.Add(6*time.Hour + 59*time.Minute),
+	}
+
+	m.Update(scheduleTickMsg(day.Add(7 * time.Hour)))
+	if m.state != stateCalendar || !m.routineDue {
+		t.Fatalf("state %d, routineDue %v; want the calendar kept and the routine remembered", m.state, m.routineDue)
+	}
+	// Long after the grace period the routine is still offered back on the countdown screen.
+	m.Update(scheduleTickMsg(day.Add(8 * time.Hour)))
+	m.state = stateCountdown
+	m.Update(tickMsg(day.Add(8 * time.Hour)))
+	if m.state != stateScheduledPrompt || m.pendingRoutine.FileName != "morning.md" {
+		t.Errorf("back on the countdown: state %d, pending %q; want the prompt for morning.md", m.state, m.pendingRoutine.FileName)
+	}
+
+	// On the countdown screen the prompt shows right away.
+	m = model{state: stateCountdown, schedules: m.schedules, lastScheduleCheck: day.AddDate(0, 0, 1).Add(6*time.Hour + 59*time.Minute)}
+	m.Update(scheduleTickMsg(day.AddDate(0, 0, 1).Add(7 * time.Hour)))
+	if m.state != stateScheduledPrompt || m.routineDue {
+		t.Errorf("on the countdown: state %d, routineDue %v; want the prompt", m.state, m.routineDue)
+	}
+
+	// A routine started early isn't offered again while it runs.
+	m = model{state: stateRunning, schedules: m.schedules, sessionStarted: true, routineFileName: "morning.md",
+		lastScheduleCheck: day.AddDate(0, 0, 2).Add(6*time.Hour + 59*time.Minute)}
+	m.Update(scheduleTickMsg(day.AddDate(0, 0, 2).Add(7 * time.Hour)))
+	if m.routineDue {
+		t.Error("the running routine was queued to be offered again")
+	}
+}
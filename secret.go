@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// sealedPrefix marks an event name that is stored encrypted on disk.
+const sealedPrefix = "enc:"
+
+const (
+	secretSaltSize   = 16
+	secretIterations = 200000
+	secretKeySize    = 32
+)
+
+var errWrongPassphrase = errors.New("wrong passphrase")
+
+// deriveKey stretches a passphrase into an AES-256 key using PBKDF2-HMAC-SHA256.
+func deriveKey(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, secretIterations, secretKeySize, sha256.New)
+}
+
+// sealSecret encrypts text with AES-GCM and returns it as a prefixed base64 token.
+func sealSecret(text, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", errors.New("a passphrase is required for secret events")
+	}
+	salt := make([]byte, secretSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	gcm, err := newSecretGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	out := append(salt, nonce...)
+	out = gcm.Seal(out, nonce, []byte(text), nil)
+	return sealedPrefix + base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+// openSecret decrypts a token produced by sealSecret.
+func openSecret(token, passphrase string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, sealedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("malformed secret: %w", err)
+	}
+	if len(raw) < secretSaltSize {
+		return "", errors.New("malformed secret: too short")
+	}
+	salt := raw[:secretSaltSize]
+	gcm, err := newSecretGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	rest := raw[secretSaltSize:]
+	if len(rest) < gcm.NonceSize() {
+		return "", errors.New("malformed secret: too short")
+	}
+	plain, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errWrongPassphrase
+	}
+	return string(plain), nil
+}
+
+func newSecretGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveKey(passphrase, salt))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// isSealed reports whether a stored event name is encrypted.
+func isSealed(name string) bool {
+	return strings.HasPrefix(name, sealedPrefix)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// deriveKey must keep producing the key existing events.md files were sealed with.
+func TestDeriveKeyStable(t *testing.T) {
+	got := hex.EncodeToString(deriveKey("correct horse battery staple", []byte("0123456789abcdef")))
+	want := "7f2c954f85f5934bde900ac77e9dfba6f55a39244eb24496bbac967f5ef3a251"
+	if got != want {
+		t.Errorf("deriveKey = %s, want %s", got, want)
+	}
+}
+
+func TestSealAndOpenSecret(t *testing.T) {
+	token, err := sealSecret("Dentist", "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isSealed(token) {
+		t.Fatalf("token %q lacks the %q prefix", token, sealedPrefix)
+	}
+	if got, err := openSecret(token, "hunter2"); err != nil || got != "Dentist" {
+		t.Errorf("openSecret = %q, %v, want %q", got, err, "Dentist")
+	}
+	if _, err := openSecret(token, "wrong"); err != errWrongPassphrase {
+		t.Errorf("openSecret with the wrong passphrase = %v, want %v", err, errWrongPassphrase)
+	}
+	if _, err := sealSecret("Dentist", ""); err == nil {
+		t.Error("sealSecret without a passphrase succeeded")
+	}
+}
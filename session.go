@@ -8,6 +8,7 @@ import (
 
     "github.com/charmbracelet/bubbles/viewport"
     "github.com/charmbracelet/glamour"
+    "github.com/charmbracelet/lipgloss"
 )
 
 
@@ -35,23 +36,22 @@ func (m *model) saveLog() {
     var logContent strings.Builder
     logContent.WriteString("\n---\n\n") // Separator for new sessions
     logContent.WriteString(fmt.Sprintf("## Session - %s\n", time.Now().Format("15:04:05")))
-
-    workMap := make(map[string]time.Duration)
-    for _, s := range m.sessions {
-        workMap[s.RoutineTitle] += s.Elapsed
-    }
+    logContent.WriteString(fmt.Sprintf("Routine: %s\n\n", routineDisplayName(m.routineFileName)))
 
     for _, routine := range m.routines {
-        if dur, ok := workMap[routine.Title]; ok {
+        if routine.visited() {
             logContent.WriteString(fmt.Sprintf("### %s\n", strings.Title(routine.Title)))
-            logContent.WriteString(fmt.Sprintf("Time Spent: %s\n", dur.Truncate(time.Second)))
+            logContent.WriteString(fmt.Sprintf("Time Spent: %s\n", routine.Elapsed.Truncate(time.Second)))
+            if planned, err := parseDuration(routine.Time); err == nil {
+                logContent.WriteString(fmt.Sprintf("Planned: %s\n", planned))
+            }
+            if routine.Paused > 0 {
+                logContent.WriteString(fmt.Sprintf("Paused: %s\n", routine.Paused.Truncate(time.Second)))
+            }
+            logContent.WriteString(fmt.Sprintf("Status: %s\n", routine.Status))
             logContent.WriteString("Checklist:\n")
             for _, item := range routine.Checklist {
-                status := "[ ]"
-                if item.Complete {
-                    status = "[x]"
-                }
-                logContent.WriteString(fmt.Sprintf("- %s %s\n", status, item.Text))
+                logContent.WriteString(checklistLine(item))
             }
             logContent.WriteString("\n")
         }
@@ -61,6 +61,20 @@ func (m *model) saveLog() {
         logContent.WriteString(fmt.Sprintf("Total Paused: %s\n", m.totalPaused.Truncate(time.Second)))
     }
 
+    if len(m.sessions) > 0 {
+        logContent.WriteString("\nSegments:\n")
+        for _, s := range m.sessions {
+            logContent.WriteString(segmentLine(s))
+        }
+
+        logContent.WriteString("\n### Planned vs actual\n\n")
+        logContent.WriteString(m.plannedVsActual())
+
+        if timeline := renderTimeline(m.sessions, m.pauses, timelineLogWidth, false); timeline != "" {
+            logContent.WriteString("\n### Timeline\n\n```\n" + timeline + "```\n")
+        }
+    }
+
     file.WriteString(logContent.String())
 }
 
@@ -68,23 +82,20 @@ func (m *model) saveLog() {
 
 // generateSummaryMarkdown generates a markdown string of the session summary.
 func (m model) generateSummaryMarkdown() string {
-	workMap := make(map[string]time.Duration)
-	for _, s := range m.sessions {
-		workMap[s.RoutineTitle] += s.Elapsed
-	}
-
 	var out strings.Builder
 	for _, routine := range m.routines {
-		if dur, ok := workMap[routine.Title]; ok {
+		if routine.visited() {
 			out.WriteString(fmt.Sprintf("### %s\n", strings.Title(routine.Title)))
-			out.WriteString(fmt.Sprintf("Time Spent: %s\n", dur.Truncate(time.Second)))
+			out.WriteString(fmt.Sprintf("Time Spent: %s\n", routine.Elapsed.Truncate(time.Second)))
+			if routine.Paused > 0 {
+				out.WriteString(fmt.Sprintf("\nPaused: %s\n", routine.Paused.Truncate(time.Second)))
+			}
+			if routine.Status == habitSkipped {
+				out.WriteString("\nStatus: skipped\n")
+			}
 			out.WriteString("\nChecklist:\n")
 			for _, item := range routine.Checklist {
-				status := "[ ]"
-				if item.Complete {
-					status = "[x]"
-				}
-				out.WriteString(fmt.Sprintf("- %s %s\n", status, item.Text))
+				out.WriteString(checklistLine(item))
 			}
 			out.WriteString("\n")
 		}
@@ -94,22 +105,57 @@ func (m model) generateSummaryMarkdown() string {
 		out.WriteString(fmt.Sprintf("Total Paused: %s\n", m.totalPaused.Truncate(time.Second)))
 	}
 
+	if len(m.sessions) > 0 {
+		out.WriteString("\n### Planned vs actual\n\n")
+		out.WriteString(m.plannedVsActual())
+	}
+
 	return out.String()
 }
 
-func (m model) stopSession() model {
-    if m.state == stateRunning {
-        m.elapsed += time.Since(m.startTime)
-        m.sessions = append(m.sessions, Session{
-            RoutineTitle: m.currentRoutine().Title,
-            Elapsed:      m.elapsed,
-        })
+// visited reports whether the habit was started or skipped during the session.
+func (r Routine) visited() bool {
+    return r.Elapsed > 0 || r.Status != habitPending
+}
+
+// segmentLine formats one visit of a habit for the log.
+func segmentLine(s Session) string {
+    line := fmt.Sprintf("- %s–%s %s: %s", s.Start.Format("15:04:05"), s.End.Format("15:04:05"),
+        s.RoutineTitle, s.Elapsed.Truncate(time.Second))
+    if s.Paused > 0 {
+        line += fmt.Sprintf(", paused %s", s.Paused.Truncate(time.Second))
+    }
+    if s.Skipped {
+        line += ", skipped"
     }
+    return line + "\n"
+}
+
+// checklistLine formats a checklist item for the summary and the log,
+// with the time actually spent on it when it was tracked.
+func checklistLine(item ChecklistItem) string {
+    status := "[ ]"
+    if item.Complete {
+        status = "[x]"
+    }
+    line := fmt.Sprintf("%s- %s %s", strings.Repeat("  ", item.Depth), status, item.Text)
+    switch {
+    case item.Budget > 0:
+        line += fmt.Sprintf(" (%s / %s)", item.Spent.Truncate(time.Second), item.Budget)
+    case item.Spent >= time.Second:
+        line += fmt.Sprintf(" (%s)", item.Spent.Truncate(time.Second))
+    }
+    return line + "\n"
+}
+
+func (m model) stopSession() model {
     if m.state == statePausing {
-        m.totalPaused += time.Since(m.pauseStart)
+        m.recordPause(m.pauseStart, time.Now())
     }
+    m.closeVisit(false)
 
     m.state = stateStopped
+    m.exportMsg = ""
 
     // Render the markdown summary once when the session stops
     // Initialize the viewport
@@ -130,7 +176,12 @@ func (m model) stopSession() model {
         if err != nil {
             vp.SetContent("Error rendering summary with glamour: " + err.Error())
         } else {
-            vp.SetContent(summary)
+            // The timeline is drawn with lipgloss above the glamour summary.
+            timeline := renderTimeline(m.sessions, m.pauses, glamourRenderWidth, true)
+            if timeline != "" {
+                timeline = lipgloss.NewStyle().Padding(1, 2).Render(routineTitleStyle.Render("Timeline") + "\n" + timeline)
+            }
+            vp.SetContent(timeline + summary)
         }
     }
 
@@ -138,6 +189,11 @@ func (m model) stopSession() model {
 
     // Save the log after generating the summary
     m.saveLog()
+    m.refreshGoals()
+    if m.sessionStarted {
+        m.runHooks(hookSessionStop)
+        m.sessionStarted = false
+    }
 
     return m
 }
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	defaultStatusFormat = `{{if .Paused}}⏸ {{end}}{{.Habit}} {{.Remaining}}`
+	defaultIdleFormat   = `{{if .Event}}{{.Event}} in {{.EventIn}}{{end}}`
+)
+
+// statusLine is what the status templates can use.
+type statusLine struct {
+	State     string // idle, ready, running, paused, break or stopped
+	Routine   string
+	Habit     string
+	Index     int
+	Count     int
+	Elapsed   string
+	Remaining string
+	Paused    bool
+	Event     string // Next event, also while a routine is active
+	EventIn   string
+	EventAt   string
+}
+
+// waybarOutput is the JSON that waybar's custom module reads.
+type waybarOutput struct {
+	Text       string `json:"text"`
+	Tooltip    string `json:"tooltip"`
+	Class      string `json:"class"`
+	Percentage int    `json:"percentage"`
+}
+
+// runStatus implements `timey status`, which prints one line for a status bar:
+// the running habit and its remaining time, or the next event when idle.
+// The running session is read through the local API, so `API` must be set in
+// config/config.md for habits to show.
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	format := fs.String("format", defaultStatusFormat, "template while a routine is active")
+	idleFormat := fs.String("idle-format", defaultIdleFormat, "template when no routine is active")
+	asJSON := fs.Bool("json", false, "print waybar JSON instead of plain text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	activeTmpl, err := template.New("format").Parse(*format)
+	if err != nil {
+		return fmt.Errorf("invalid -format: %w", err)
+	}
+	idleTmpl, err := template.New("idle-format").Parse(*idleFormat)
+	if err != nil {
+		return fmt.Errorf("invalid -idle-format: %w", err)
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	line, percentage := currentStatus(cfg, time.Now())
+
+	tmpl := idleTmpl
+	if line.Habit != "" {
+		tmpl = activeTmpl
+	}
+	var text strings.Builder
+	if err := tmpl.Execute(&text, line); err != nil {
+		return err
+	}
+
+	if !*asJSON {
+		fmt.Println(text.String())
+		return nil
+	}
+	return json.NewEncoder(os.Stdout).Encode(waybarOutput{
+		Text:       text.String(),
+		Tooltip:    statusTooltip(line),
+		Class:      line.State,
+		Percentage: percentage,
+	})
+}
+
+// currentStatus asks a running timey for its state, falling back to the
+// events file when it isn't reachable. It also returns how far along the
+// current habit is, in percent.
+func currentStatus(cfg Config, now time.Time) (statusLine, int) {
+	line := statusLine{State: "idle"}
+	percentage := 0
+
+	var events []apiEventInfo
+	if s, err := fetchAPIState(cfg.API); err == nil {
+		line.State = s.State
+		line.Routine = s.Routine
+		line.Habit = s.Habit
+		line.Index = s.Index
+		line.Count = s.Count
+		line.Paused = s.Paused
+		line.Elapsed = compactDuration(time.Duration(s.Elapsed) * time.Second)
+		line.Remaining = compactDuration(time.Duration(s.Remaining) * time.Second)
+		if total := s.Elapsed + s.Remaining; total > 0 {
+			percentage = s.Elapsed * 100 / total
+		}
+		events = s.Events
+	} else if loaded, err := loadEvents("events/events.md"); err == nil {
+		for _, u := range upcomingEvents(loaded, now) {
+			events = append(events, apiEventInfo{Name: eventDisplayName(u.Event), Next: u.Next})
+		}
+	}
+
+	for _, e := range events {
+		if e.Next.After(now) {
+			line.Event = e.Name
+			line.EventIn = compactDuration(e.Next.Sub(now))
+			line.EventAt = e.Next.Format("Mon 15:04")
+			break
+		}
+	}
+	return line, percentage
+}
+
+// statusTooltip is the longer description shown on hover in waybar.
+func statusTooltip(line statusLine) string {
+	var parts []string
+	if line.Habit != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s (%d/%d), %s left", line.Routine, line.Habit, line.Index, line.Count, line.Remaining))
+	}
+	if line.Event != "" {
+		parts = append(parts, fmt.Sprintf("Next event: %s, %s", line.Event, line.EventAt))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// compactDuration formats d for a status bar: "4:05", "1h05m" or "3d4h".
+func compactDuration(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+	}
+}
+
+// fetchAPIState reads GET /state from a running timey at addr.
+func fetchAPIState(addr string) (apiStatus, error) {
+	if addr == "" {
+		return apiStatus{}, fmt.Errorf("the API is off")
+	}
+	network, address, base := "tcp", addr, "http://"+addr
+	if path, ok := apiSocketPath(addr); ok {
+		network, address, base = "unix", path, "http://timey"
+	} else if strings.HasPrefix(addr, ":") {
+		address, base = "127.0.0.1"+addr, "http://127.0.0.1"+addr
+	}
+
+	client := http.Client{
+		Timeout: time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, address)
+			},
+		},
+	}
+	resp, err := client.Get(base + "/state")
+	if err != nil {
+		return apiStatus{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiStatus{}, fmt.Errorf("timey answered %s", resp.Status)
+	}
+	var s apiStatus
+	err = json.NewDecoder(resp.Body).Decode(&s)
+	return s, err
+}
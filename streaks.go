@@ -0,0 +1,174 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// streakHistoryDays is how far back the logs are read for streaks and the heatmap.
+const streakHistoryDays = 365
+
+// streakStats is the consistency of one routine or habit over the log history.
+type streakStats struct {
+	Name    string
+	Kind    string         // "all", "routine" or "habit"
+	Days    map[string]int // Completions per day, keyed by 2006-01-02
+	First   time.Time      // First day it appears in the logs
+	Current int            // Consecutive days up to today, or yesterday if today is still open
+	Longest int
+	Rate    float64 // Share of days since First with a completion
+}
+
+// dayKey is the map key used for a day in streakStats.Days.
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// buildStreaks computes streaks for all habits together, for each routine and for each habit.
+// A habit counts on a day when it was marked done. A routine counts on a day when
+// one of its sessions finished at least one habit and left none unfinished.
+func buildStreaks(entries []logEntry, now time.Time) []streakStats {
+	all := streakStats{Name: "All habits", Kind: "all", Days: make(map[string]int)}
+	routines := make(map[string]*streakStats)
+	habits := make(map[string]*streakStats)
+	var routineOrder, habitOrder []string
+
+	get := func(set map[string]*streakStats, order *[]string, name, kind string) *streakStats {
+		key := strings.ToLower(name)
+		if s, ok := set[key]; ok {
+			return s
+		}
+		set[key] = &streakStats{Name: name, Kind: kind, Days: make(map[string]int)}
+		*order = append(*order, key)
+		return set[key]
+	}
+	seen := func(s *streakStats, day time.Time) {
+		if s.First.IsZero() || day.Before(s.First) {
+			s.First = day
+		}
+	}
+
+	// Group habits by session so routines can be judged as a whole.
+	type sessionResult struct {
+		routine string
+		day     time.Time
+		done    int
+		open    bool
+	}
+	var sessions []*sessionResult
+	bySession := make(map[time.Time]*sessionResult)
+
+	for _, e := range entries {
+		day := startOfDay(e.Date)
+		seen(&all, day)
+		h := get(habits, &habitOrder, e.Habit, "habit")
+		seen(h, day)
+		if e.Status == habitDone.String() {
+			all.Days[dayKey(day)]++
+			h.Days[dayKey(day)]++
+		}
+
+		if e.Routine == "" {
+			continue
+		}
+		s, ok := bySession[e.Date]
+		if !ok {
+			s = &sessionResult{routine: e.Routine, day: day}
+			bySession[e.Date] = s
+			sessions = append(sessions, s)
+		}
+		switch e.Status {
+		case habitDone.String():
+			s.done++
+		case habitPending.String():
+			s.open = true
+		}
+	}
+	for _, s := range sessions {
+		r := get(routines, &routineOrder, s.routine, "routine")
+		seen(r, s.day)
+		if s.done > 0 && !s.open {
+			r.Days[dayKey(s.day)]++
+		}
+	}
+
+	stats := []streakStats{all}
+	for _, group := range []struct {
+		set   map[string]*streakStats
+		order []string
+	}{{routines, routineOrder}, {habits, habitOrder}} {
+		var list []streakStats
+		for _, key := range group.order {
+			list = append(list, *group.set[key])
+		}
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		stats = append(stats, list...)
+	}
+	for i := range stats {
+		stats[i].measure(now)
+	}
+	return stats
+}
+
+// measure fills in the current and longest streak and the completion rate.
+func (s *streakStats) measure(now time.Time) {
+	today := startOfDay(now)
+	if s.First.IsZero() {
+		return
+	}
+
+	run, total, completed := 0, 0, 0
+	for day := s.First; !day.After(today); day = day.AddDate(0, 0, 1) {
+		total++
+		if s.Days[dayKey(day)] > 0 {
+			completed++
+			run++
+			s.Longest = max(s.Longest, run)
+		} else {
+			run = 0
+		}
+	}
+	s.Rate = float64(completed) / float64(total)
+
+	// Today doesn't break the streak until it is over.
+	day := today
+	if s.Days[dayKey(day)] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for s.Days[dayKey(day)] > 0 {
+		s.Current++
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+// openStreaks reads the log history and shows the streaks screen.
+func (m *model) openStreaks() {
+	now := time.Now()
+	entries, err := loadLogEntries(logDir, now.AddDate(0, 0, -streakHistoryDays), now)
+	m.streaksErr = err
+	m.streaks = buildStreaks(entries, now)
+	m.streakCursor = 0
+	m.state = stateStreaks
+}
+
+// updateStreaks handles keys for the streaks screen.
+func (m *model) updateStreaks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		m.state = stateCountdown
+		m.refreshCountdowns()
+		return m, tea.Batch(m.countdownSpinner.Tick, tick())
+	case "up", "k":
+		if m.streakCursor > 0 {
+			m.streakCursor--
+		}
+	case "down", "j":
+		if m.streakCursor < len(m.streaks)-1 {
+			m.streakCursor++
+		}
+	}
+	return m, nil
+}
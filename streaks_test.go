@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildStreaks(t *testing.T) {
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.Local)
+	at := func(daysAgo, hour int) time.Time {
+		return startOfDay(now).AddDate(0, 0, -daysAgo).Add(time.Duration(hour) * time.Hour)
+	}
+	entries := []logEntry{
+		// Morning is finished four days in a row up to yesterday, with a gap before.
+		{Date: at(6, 7), Routine: "Morning", Habit: "Stretch", Status: "done"},
+		{Date: at(4, 7), Routine: "Morning", Habit: "Stretch", Status: "done"},
+		{Date: at(3, 7), Routine: "Morning", Habit: "Stretch", Status: "done"},
+		{Date: at(2, 7), Routine: "Morning", Habit: "Stretch", Status: "done"},
+		{Date: at(2, 7), Routine: "Morning", Habit: "Read", Status: "skipped"},
+		{Date: at(1, 7), Routine: "Morning", Habit: "Stretch", Status: "done"},
+		// Today's session was left unfinished, so the routine doesn't count today
+		// but its streak isn't broken until the day is over.
+		{Date: at(0, 7), Routine: "Morning", Habit: "Stretch", Status: "done"},
+		{Date: at(0, 7), Routine: "Morning", Habit: "Read", Status: "unfinished"},
+	}
+	stats := buildStreaks(entries, now)
+
+	want := []struct {
+		name, kind       string
+		current, longest int
+		rate             float64
+	}{
+		{"All habits", "all", 5, 5, 6.0 / 7},
+		{"Morning", "routine", 4, 4, 5.0 / 7},
+		{"Read", "habit", 0, 0, 0},
+		{"Stretch", "habit", 5, 5, 6.0 / 7},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got %d stats %+v, want %d", len(stats), stats, len(want))
+	}
+	for i, w := range want {
+		s := stats[i]
+		if s.Name != w.name || s.Kind != w.kind || s.Current != w.current || s.Longest != w.longest || s.Rate != w.rate {
+			t.Errorf("stats %d = %s %s current %d longest %d rate %.3f, want %s %s %d %d %.3f",
+				i, s.Name, s.Kind, s.Current, s.Longest, s.Rate, w.name, w.kind, w.current, w.longest, w.rate)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	today := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	days := func(daysAgo ...int) map[string]int {
+		m := make(map[string]int)
+		for _, d := range daysAgo {
+			m[dayKey(today.AddDate(0, 0, -d))]++
+		}
+		return m
+	}
+	tests := []struct {
+		name             string
+		first            int // Days ago
+		days             map[string]int
+		current, longest int
+	}{
+		{"done today", 2, days(0, 1, 2), 3, 3},
+		{"today still open", 2, days(1, 2), 2, 2},
+		{"broken yesterday", 5, days(0, 3, 4, 5), 1, 3},
+		{"never done", 3, days(), 0, 0},
+	}
+	for _, tt := range tests {
+		s := streakStats{Days: tt.days, First: today.AddDate(0, 0, -tt.first)}
+		s.measure(today.Add(12 * time.Hour))
+		if s.Current != tt.current || s.Longest != tt.longest {
+			t.Errorf("%s: current %d longest %d, want %d %d", tt.name, s.Current, s.Longest, tt.current, tt.longest)
+		}
+	}
+}
+
+func TestStreaksFromLog(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Mon, 19 Oct 2026.md"), []byte(testLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 20, 0, 0, 0, time.Local)
+	entries, err := loadLogEntries(dir, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counted := make(map[string]int)
+	for _, s := range buildStreaks(entries, now) {
+		counted[s.Name] = s.Days[dayKey(now)]
+	}
+	// Morning finished one habit and skipped the other, Lunch left C unfinished.
+	want := map[string]int{"All habits": 2, "Morning": 1, "Lunch": 0, "Wake Up": 1, "Stretch": 0, "A": 1, "B": 0, "C": 0}
+	for name, n := range want {
+		if counted[name] != n {
+			t.Errorf("%s counted %d times today, want %d", name, counted[name], n)
+		}
+	}
+	if len(counted) != len(want) {
+		t.Errorf("streaks for %v, want %v", counted, want)
+	}
+}
+
+func TestRenderHeatmapWeekStart(t *testing.T) {
+	wednesday := time.Date(2026, 10, 21, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		weekStart time.Weekday
+		labels    []string // Every other row is labelled
+		cells     int      // Two full weeks and the days of this one so far
+	}{
+		{time.Monday, []string{"Tue", "Thu", "Sat"}, 14 + 3},
+		{time.Sunday, []string{"Mon", "Wed", "Fri"}, 14 + 4},
+	}
+	for _, tt := range tests {
+		out := renderHeatmap(streakStats{Days: map[string]int{}}, 3, wednesday, tt.weekStart)
+		rows := strings.Split(out, "\n")[1:8]
+		for i, label := range tt.labels {
+			if !strings.Contains(rows[2*i+1], label) {
+				t.Errorf("week from %s: row %d is %q, want it labelled %s", tt.weekStart, 2*i+1, rows[2*i+1], label)
+			}
+		}
+		if got := strings.Count(strings.Join(rows, "\n"), "■"); got != tt.cells {
+			t.Errorf("week from %s: %d days drawn, want %d", tt.weekStart, got, tt.cells)
+		}
+	}
+}
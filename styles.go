@@ -20,6 +20,7 @@ var (
 	// New style for checklist items to ensure consistent indentation.
 	checklistStyle        = lipgloss.NewStyle().PaddingLeft(2)
 	focusedChecklistStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("205"))
+	habitNoteStyle        = lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("245"))
 	spinnerStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 	pauseTimerStyle       = lipgloss.NewStyle().
 				Bold(true).
@@ -62,6 +63,34 @@ var (
 		Foreground(lipgloss.Color("205")).
 		Align(lipgloss.Center)
 
+	goalsBoxStyle = lipgloss.NewStyle().
+		Width(40).
+		BorderStyle(lipgloss.RoundedBorder()).
+		Foreground(lipgloss.Color("69")).
+		Align(lipgloss.Center)
+
+	contextQuoteStyle = lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("69"))
+
+	// Focus mode clock
+	focusDigitStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	focusPausedDigitStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	// Heatmap cells from no completions to the most in a day
+	heatmapLevels = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("237")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("22")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("28")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("34")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("46")),
+	}
+
+	// Calendar styles
+	calendarHeaderStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("69"))
+	calendarTodayStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("230")).Background(lipgloss.Color("27"))
+	calendarSelectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("27")).Background(lipgloss.Color("230"))
+	calendarBusyStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	calendarOtherStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
 	styled = lipgloss.NewStyle().
         Bold(true).
         Foreground(lipgloss.Color("230")).
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	includeRE  = regexp.MustCompile(`^(?:-\s*)?Include:\s*(\S+)(.*)$`)
+	varDefRE   = regexp.MustCompile(`^(?:-\s*)?Var:\s*(\w+)\s*=\s*(.*)$`)
+	varParamRE = regexp.MustCompile(`(\w+)=("[^"]*"|\S+)`)
+	varUseRE   = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+)
+
+// expandRoutineFile reads a routine file and returns its lines with `Include:` directives
+// inlined and `{{name}}` variables substituted. Variables passed in vars (from an including
+// file or include parameters) take precedence over `Var:` defaults defined in the file itself.
+func expandRoutineFile(path string, vars map[string]string) ([]string, error) {
+	return expandRoutineLines(path, vars, map[string]bool{})
+}
+
+func expandRoutineLines(path string, vars map[string]string, seen map[string]bool) ([]string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if seen[abs] {
+		return nil, fmt.Errorf("%s: include cycle", path)
+	}
+	seen[abs] = true
+	defer delete(seen, abs)
+
+	raw, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the file's own defaults first so variables can be used before they are defined.
+	scope := make(map[string]string)
+	for _, line := range raw {
+		if match := varDefRE.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			scope[match[1]] = strings.TrimSpace(match[2])
+		}
+	}
+	for k, v := range vars {
+		scope[k] = v
+	}
+
+	var out []string
+	for _, line := range raw {
+		trimmed := strings.TrimSpace(line)
+		if varDefRE.MatchString(trimmed) {
+			continue
+		}
+
+		expanded, err := substituteVars(line, scope)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if match := includeRE.FindStringSubmatch(strings.TrimSpace(expanded)); match != nil {
+			params := make(map[string]string)
+			for k, v := range scope {
+				params[k] = v
+			}
+			for _, p := range varParamRE.FindAllStringSubmatch(match[2], -1) {
+				params[p[1]] = strings.Trim(p[2], `"`)
+			}
+			target, err := includePath(path, match[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			included, err := expandRoutineLines(target, params, seen)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, included...)
+			continue
+		}
+		out = append(out, expanded)
+	}
+	return out, nil
+}
+
+// includePath resolves an include target relative to the routines directory,
+// adding the .md extension when it is left out. Targets outside the routines
+// directory are refused.
+func includePath(from, target string) (string, error) {
+	if !strings.HasSuffix(target, ".md") {
+		target += ".md"
+	}
+	rel := filepath.Clean(filepath.FromSlash(target))
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("include %q is outside the routines directory", target)
+	}
+	return filepath.Join(routinesRoot(from), rel), nil
+}
+
+// routinesRoot returns the "routines" directory that contains path, or path's own directory.
+func routinesRoot(path string) string {
+	dir := filepath.Dir(path)
+	for d := dir; ; d = filepath.Dir(d) {
+		if filepath.Base(d) == "routines" {
+			return d
+		}
+		if parent := filepath.Dir(d); parent == d {
+			return dir
+		}
+	}
+}
+
+// substituteVars replaces every {{name}} in line with its value from vars.
+func substituteVars(line string, vars map[string]string) (string, error) {
+	var missing string
+	out := varUseRE.ReplaceAllStringFunc(line, func(use string) string {
+		name := varUseRE.FindStringSubmatch(use)[1]
+		v, ok := vars[name]
+		if !ok {
+			missing = name
+			return use
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("undefined variable %q", missing)
+	}
+	return out, nil
+}
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
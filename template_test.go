@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRoutineFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "routines")
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestExpandRoutineFile(t *testing.T) {
+	root := writeRoutineFiles(t, map[string]string{
+		"shared/stretch.md": "- Var: minutes = 5\n1. Stretch\n- Time: {{minutes}}m\n",
+		"shared/loop.md":    "- Include: shared/loop\n",
+		"morning.md":        "- Var: name = Sam\n1. Hello {{ name }}\n- Time: 1m\n- Include: shared/stretch minutes=10\n",
+		"evening.md":        "- Include: shared/stretch\n",
+		"bare.md":           "1. Read {{book}}\n",
+		"cycle.md":          "- Include: shared/loop.md\n",
+		"escape.md":         "- Include: ../secrets\n",
+		"sneaky.md":         "- Include: shared/../../secrets.md\n",
+		"absolute.md":       "- Include: /etc/passwd\n",
+		"within.md":         "- Include: shared/../bare book=Dune\n",
+	})
+	if err := os.WriteFile(filepath.Join(filepath.Dir(root), "secrets.md"), []byte("1. Secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file    string
+		vars    map[string]string
+		want    string
+		wantErr string
+	}{
+		{file: "morning.md", want: "1. Hello Sam|- Time: 1m|1. Stretch|- Time: 10m"},
+		{file: "morning.md", vars: map[string]string{"name": "Alex"}, want: "1. Hello Alex|- Time: 1m|1. Stretch|- Time: 10m"},
+		{file: "evening.md", want: "1. Stretch|- Time: 5m"},
+		{file: "bare.md", vars: map[string]string{"book": "Dune"}, want: "1. Read Dune"},
+		{file: "bare.md", wantErr: "undefined variable"},
+		{file: "cycle.md", wantErr: "include cycle"},
+		{file: "escape.md", wantErr: "outside the routines directory"},
+		{file: "sneaky.md", wantErr: "outside the routines directory"},
+		{file: "absolute.md", wantErr: "outside the routines directory"},
+		{file: "within.md", want: "1. Read Dune"},
+	}
+	for _, tt := range tests {
+		got, err := expandRoutineFile(filepath.Join(root, tt.file), tt.vars)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want %q", tt.file, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.file, err)
+			continue
+		}
+		if joined := strings.Join(got, "|"); joined != tt.want {
+			t.Errorf("%s with %v = %q, want %q", tt.file, tt.vars, joined, tt.want)
+		}
+	}
+}
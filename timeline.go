@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	timelineLabelWidth = 18
+	timelineLogWidth   = 72 // Width of the timeline written into the log
+	pausedRowLabel     = "Paused"
+)
+
+// timelineColors cycles through bar colors for the habit rows.
+var timelineColors = []lipgloss.Color{"12", "205", "69", "42", "214", "141"}
+
+// renderTimeline draws each habit's segments and the session's pauses as
+// horizontal bars against wall-clock time. With color off it is plain text for the log.
+func renderTimeline(sessions []Session, pauses []pauseSpan, width int, color bool) string {
+	if len(sessions) == 0 {
+		return ""
+	}
+	from, to := sessions[0].Start, sessions[0].End
+	for _, s := range sessions {
+		if s.Start.Before(from) {
+			from = s.Start
+		}
+		if s.End.After(to) {
+			to = s.End
+		}
+	}
+	for _, p := range pauses {
+		if p.Start.Before(from) {
+			from = p.Start
+		}
+		if p.End.After(to) {
+			to = p.End
+		}
+	}
+
+	barWidth := width - timelineLabelWidth - 1
+	if barWidth < 10 || !to.After(from) {
+		return ""
+	}
+	col := func(t time.Time) int {
+		return int(float64(t.Sub(from)) / float64(to.Sub(from)) * float64(barWidth-1))
+	}
+	fill := func(row []rune, start, end time.Time, r rune) {
+		for c := col(start); c <= col(end); c++ {
+			row[c] = r
+		}
+	}
+	newRow := func() []rune {
+		return []rune(strings.Repeat("·", barWidth))
+	}
+
+	// One row per habit, in the order they were first visited.
+	var titles []string
+	rows := make(map[string][]rune)
+	for _, s := range sessions {
+		if _, ok := rows[s.RoutineTitle]; !ok {
+			titles = append(titles, s.RoutineTitle)
+			rows[s.RoutineTitle] = newRow()
+		}
+		mark := '█'
+		if s.Skipped {
+			mark = '×'
+		}
+		fill(rows[s.RoutineTitle], s.Start, s.End, mark)
+	}
+	if len(pauses) > 0 {
+		titles = append(titles, pausedRowLabel)
+		rows[pausedRowLabel] = newRow()
+		for _, p := range pauses {
+			fill(rows[pausedRowLabel], p.Start, p.End, '░')
+		}
+	}
+
+	var b strings.Builder
+	for i, title := range titles {
+		bar := string(rows[title])
+		if color {
+			c := timelineColors[i%len(timelineColors)]
+			if title == pausedRowLabel {
+				c = lipgloss.Color("240")
+			}
+			bar = lipgloss.NewStyle().Foreground(c).Render(bar)
+		}
+		b.WriteString(fmt.Sprintf("%-*s %s\n", timelineLabelWidth, truncateLabel(title, timelineLabelWidth), bar))
+	}
+
+	start, end := from.Format("15:04"), to.Format("15:04")
+	gap := barWidth - len(start) - len(end)
+	if gap < 1 {
+		gap = 1
+	}
+	b.WriteString(fmt.Sprintf("%-*s %s%s%s\n", timelineLabelWidth, "", start, strings.Repeat(" ", gap), end))
+	return b.String()
+}
+
+// truncateLabel shortens a row label so the bars line up.
+func truncateLabel(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// plannedVsActual renders a markdown table comparing each habit's planned time with the time spent.
+func (m model) plannedVsActual() string {
+	var b strings.Builder
+	b.WriteString("| Habit | Planned | Actual | Difference |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, r := range m.routines {
+		if !r.visited() {
+			continue
+		}
+		planned, err := parseDuration(r.Time)
+		if err != nil {
+			planned = 0
+		}
+		actual := r.Elapsed.Truncate(time.Second)
+		diff := actual - planned
+		sign := "+"
+		if diff < 0 {
+			sign = "-"
+			diff = -diff
+		}
+		note := ""
+		if r.Status == habitSkipped {
+			note = " (skipped)"
+		}
+		b.WriteString(fmt.Sprintf("| %s%s | %s | %s | %s%s |\n", r.Title, note, planned, actual, sign, diff))
+	}
+	return b.String()
+}
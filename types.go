@@ -25,21 +25,62 @@ func (i fileItem) Description() string { return "" }
 
 // ChecklistItem represents a single to-do item in a routine.
 type ChecklistItem struct {
-	Text     string
-	Complete bool
+	Text      string
+	Complete  bool
+	Depth     int  // Nesting level, 0 for top-level items
+	Collapsed bool // Sub-items are hidden in the running view
+	Budget    time.Duration // Optional time budget, e.g. "(5m)" after the text
+	Spent     time.Duration // Time the item was selected while running
 }
 
 // Routine holds the data for a single routine, including its title, time, and checklist.
 type Routine struct {
 	Title     string
 	Time      string
+	At        string // Optional time of day the habit is tied to, e.g. "07:30"
+	Notes     []string
+	Status    habitStatus           // Progress of the habit in the running session
+	Elapsed   time.Duration         // Run time over all visits in the running session
+	Paused    time.Duration         // Pause time over all visits in the running session
+	Optional  bool                  // Can be skipped with a single key
+	Days      map[time.Weekday]bool // Weekdays the habit runs on, empty means every day
 	Checklist []ChecklistItem
 }
 
-// Session tracks the duration of each routine segment.
+// pauseSpan is a stretch of wall-clock time the session was paused.
+type pauseSpan struct {
+	Start time.Time
+	End   time.Time
+}
+
+// habitStatus is where a habit stands in the running session.
+type habitStatus int
+
+const (
+	habitPending habitStatus = iota
+	habitDone
+	habitSkipped
+)
+
+// String is the status as written in the session log.
+func (s habitStatus) String() string {
+	switch s {
+	case habitDone:
+		return "done"
+	case habitSkipped:
+		return "skipped"
+	}
+	return "unfinished"
+}
+
+// Session records one visit to a habit. Going back to a habit adds another segment.
 type Session struct {
 	RoutineTitle string
-	Elapsed      time.Duration
+	Start        time.Time
+	End          time.Time
+	Elapsed      time.Duration // Run time during the visit
+	Paused       time.Duration // Time the visit spent paused
+	Skipped      bool
 }
 
 // A tickMsg is sent on a regular interval to update the timer.
@@ -60,6 +101,13 @@ const (
 	stateReadyToStart
 	stateAddRoutine 
 	stateAddEvent
+	stateUnlockEvents
+	stateCalendar
+	stateAgenda
+	stateScheduledPrompt
+	stateOutline
+	stateStreaks
+	stateQuotesManager
 )
 
 // stage represents the current state of the routine builder.
@@ -76,6 +124,8 @@ const (
 type Quote struct {
     Text string
     Author string
+    Source string   // Book, talk or link, optional
+    Tags   []string // Lowercase, without the leading #
 }
 
 
@@ -84,6 +134,7 @@ type Event struct {
 	DateTime   time.Time
 	Repeat     string // Optional repeat pattern (e.g., "daily", "weekly", "monthly", "yearly")
 	CodePhrase string // Optional code phrase for the event
+	Sealed     string // Encrypted name as stored on disk, set for code-phrase events
 }	
 
 type eventStage int
@@ -93,6 +144,7 @@ const (
 	eventStageTime
 	eventStageRepeat
 	eventStageCodePhrase
+	eventStagePassphrase
 	eventStageDone
 )
 
@@ -110,8 +162,18 @@ type model struct {
 
 	pauseStart    time.Time
 	totalPaused   time.Duration
+	visitStart    time.Time     // when the open visit to the current habit began
+	visitPaused   time.Duration // pause time of the open visit
+	pauses        []pauseSpan   // every pause and break of the session, for the timeline
 	state         appState
 	selectedTodo  int
+	itemStart     time.Time // when time on the selected checklist item was last counted
+	budgetStart   time.Time     // itemStart at the last budget check
+	budgetSpent   time.Duration // the selected item's time at the last budget check
+	itemProgress  progress.Model
+
+	focusMode      bool // full-screen clock instead of the running view
+	focusChecklist bool // whether focus mode shows the checklist
 
 	sessions        []Session
 	progress        progress.Model
@@ -128,6 +190,8 @@ type model struct {
 	//  rendered summary.
 	summaryRendered string 
 	viewport viewport.Model
+	// result of exporting the summary, shown under it
+	exportMsg string
 
 	// fields for routine builder functionality
 	textInput        textinput.Model
@@ -142,14 +206,21 @@ type model struct {
 	renderer         *glamour.TermRenderer 
 
 	
-	countdownRemaining time.Duration
+	countdowns         []countdown
 	countdownSpinner   spinner.Model
 
 	// Used to signal exit from countdown, not global quit
 	countdownQuitting  bool 
 	countdownGreetText string
+	goals              []goalProgress // Progress towards config/goals.md, shown next to the greeting
 
 	quotes []Quote
+	quoteWarnings int // Lines of quotes.md that could not be read as a quote
+	quoteIndex     int          // Quote on the quotes screen
+	quoteTrail     []int        // Quotes shown since start, for the previous key
+	quoteTrailPos  int
+	quoteHistory   []quoteShown // When quotes were shown, so they don't repeat within a week
+	quoteRotateGen int
 
 	events []Event 
 	err               error
@@ -163,6 +234,57 @@ type model struct {
 	currentEventTime  string
 	eventRenderer    *glamour.TermRenderer
 	eventRepeat	 	  string // Optional repeat pattern for the event
+	eventCodePhrase   string // Code phrase waiting for a passphrase before saving
+
+	// secret events
+	passphraseInput  textinput.Model
+	// passphrase for sealed events, kept in memory for this session only
+	secretPassphrase string
+	// first entry of a passphrase nothing is sealed with yet, until it is typed again
+	newPassphrase    string
+	unlockErr        string
+
+	// user settings from config/config.md
+	config Config
+
+	// scrollable event list on the countdown screen
+	countdownViewport viewport.Model
+	showAllEvents     bool
+
+	// session outline overlay
+	outlineCursor    int
+	outlineReturn    appState
+	outlineInserting bool
+	outlineInput     textinput.Model
+	outlineErr       string
+
+	// scheduled routines
+	schedules         []scheduledRoutine
+	lastScheduleCheck time.Time
+	pendingRoutine    scheduledRoutine
+	routineDue        bool // pendingRoutine came due on another screen, prompt on the countdown screen
+
+	// calendar and agenda views
+	calendarDay     time.Time
+	scheduledHabits []scheduledHabit
+	agendaViewport  viewport.Model
+
+	// sessionStarted is set once the first habit starts, for the session_start and session_stop hooks
+	sessionStarted bool
+
+	// quotes manager
+	quoteCursor        int
+	quoteEditing       bool
+	quoteEditIndex     int // Quote being edited, -1 for a new one
+	quoteField         int
+	quoteInputs        []textinput.Model
+	quoteConfirmDelete bool
+	quoteErr           string
+
+	// streaks screen
+	streaks      []streakStats
+	streakCursor int
+	streaksErr   error
 
 }
 
@@ -22,30 +22,38 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.progress.Width = m.width - 4
+		m.itemProgress.Width = m.width / 2
 		m.updatePaneSizes()
 
 	case tea.KeyMsg:
+		// Passphrase prompts take every key so that letters like 'q' can be typed.
+		if m.state == stateUnlockEvents || (m.state == stateAddEvent && m.eventBuilderStage == eventStagePassphrase) {
+			return m.updatePassphrase(msg)
+		}
+		if m.state == stateCalendar || m.state == stateAgenda {
+			return m.updateCalendar(msg)
+		}
+		if m.state == stateOutline {
+			return m.updateOutline(msg)
+		}
+		if m.state == stateStreaks {
+			return m.updateStreaks(msg)
+		}
+		if m.state == stateQuotesManager {
+			return m.updateQuotesManager(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			switch m.state {
 			case stateCountdown, stateQuotes:
 				return m, tea.Quit
-			case stateRunning, statePausing, statePaused:
-				if m.state == stateRunning {
-					m.elapsed += time.Since(m.startTime)
-				}
-				if m.state == statePausing {
-					m.totalPaused += time.Since(m.pauseStart)
-				}
-				m.sessions = append(m.sessions, Session{
-					RoutineTitle: m.currentRoutine().Title,
-					Elapsed:      m.elapsed,
-				})
+			case stateRunning, statePausing, statePaused, stateReadyToStart:
 				*m = m.stopSession()
 				return m, nil
 			default:
 				m.state = stateCountdown
-				m.countdownRemaining = timeLeftToday()
+				m.refreshCountdowns()
 				return m, tea.Batch(m.countdownSpinner.Tick, tick())
 			}
 
@@ -61,8 +69,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = stateCountdown
 				return m, tea.Batch(m.countdownSpinner.Tick, tick())
 			} else if m.state == stateCountdown {
-				m.state = stateQuotes
-				return m, nil
+				return m, m.openQuotes()
 			}
 
 		case "a":
@@ -77,10 +84,11 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "s":
 			if m.state == statePausing || m.state == statePaused {
-				m.totalPaused += time.Since(m.pauseStart)
-				m.startTime = time.Now()
-				m.state = stateRunning
-				return m, tick()
+				return m, m.resumeHabit()
+			}
+			if m.state == stateQuotes || m.state == stateCountdown {
+				m.openStreaks()
+				return m, nil
 			}
 
 		case "e":
@@ -96,6 +104,64 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, textinput.Blink
 			}
 
+		case "c":
+			if m.state == stateQuotes || m.state == stateCountdown {
+				m.openCalendar()
+				return m, nil
+			}
+
+		case "g":
+			if m.state == stateQuotes || m.state == stateCountdown {
+				m.openCalendar()
+				m.openAgenda()
+				return m, nil
+			}
+
+		case "n", "]":
+			if m.state == stateQuotes {
+				m.nextQuote()
+				return m, nil
+			}
+
+		case "p", "[":
+			if m.state == stateQuotes {
+				m.previousQuote()
+				return m, nil
+			}
+
+		case "f":
+			if m.state == stateQuotes {
+				m.toggleFavorite()
+				return m, nil
+			}
+
+		case "m":
+			if m.state == stateQuotes {
+				m.openQuotesManager()
+				return m, nil
+			}
+			if m.state == stateCountdown {
+				m.showAllEvents = !m.showAllEvents
+				m.refreshCountdownEvents()
+				return m, nil
+			}
+
+		case "up", "down", "pgup", "pgdown":
+			if m.state == stateCountdown {
+				m.countdownViewport, cmd = m.countdownViewport.Update(msg)
+				return m, cmd
+			}
+
+		case "u":
+			if m.state == stateCountdown && hasLockedEvents(m.events) {
+				m.state = stateUnlockEvents
+				m.unlockErr = ""
+				m.newPassphrase = ""
+				m.passphraseInput.Reset()
+				m.passphraseInput.Focus()
+				return m, textinput.Blink
+			}
+
 		case "enter":
 			switch m.state {
 			case stateFilePicker:
@@ -103,51 +169,11 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if !ok {
 					return m, nil
 				}
-				path := fmt.Sprintf("routines/%s", selectedItem.fileName)
-				content, err := os.ReadFile(path)
-				if err != nil {
-					m.viewport.SetContent("Error reading file: " + err.Error())
-					return m, nil
-				}
-				glamourRenderWidth := m.viewport.Width - 2
-				renderer, err := glamour.NewTermRenderer(
-					glamour.WithAutoStyle(),
-					glamour.WithWordWrap(glamourRenderWidth),
-				)
-				if err != nil {
-					m.viewport.SetContent("Error rendering content: " + err.Error())
-				} else {
-					renderedContent, err := renderer.Render(string(content))
-					if err != nil {
-						m.viewport.SetContent("Error rendering content: " + err.Error())
-					} else {
-						m.viewport.SetContent(renderedContent)
-					}
-				}
-				m.viewport.GotoTop()
-				routines, err := loadRoutines(path)
-				if err != nil {
-					return m, nil
-				}
-				m.routines = routines
-				m.routineFileName = selectedItem.fileName
-				m.state = stateRoutineView
-				m.updatePaneSizes()
-				m.elapsed = 0
-				m.current = 0
-				m.selectedTodo = 0
-				m.sessions = []Session{}
+				m.openRoutineFile(selectedItem.fileName)
 				return m, nil
 
-			case stateRoutineView:
-				m.state = stateRunning
-				m.startTime = time.Now()
-				return m, tick()
-
-			case stateReadyToStart:
-				m.state = stateRunning
-				m.startTime = time.Now()
-				return m, tick()
+			case stateRoutineView, stateReadyToStart:
+				return m, m.startHabit()
 
 			case stateAddRoutine:
 				val := strings.TrimSpace(m.textInput.Value())
@@ -165,9 +191,10 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if strings.ToLower(val) == "done" {
 						m.builderStage = stageDone
 						_ = os.WriteFile(m.filename, []byte(m.routineMarkdown), 0644)
+						m.schedules = loadRoutineSchedules("routines")
 						m.textInput.Blur()
 						m.state = stateCountdown
-						m.countdownRemaining = timeLeftToday()
+						m.refreshCountdowns()
 						return m, tea.Batch(m.countdownSpinner.Tick, tick())
 					}
 					m.currentHabitName = val
@@ -240,33 +267,17 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					m.eventMarkdown += fmt.Sprintf("- Code Phrase: %s\n", codePhrase)
 
-					// Parse event time
-					t, err := parseDate(m.currentEventTime)
-					if err != nil {
-						m.eventMarkdown += fmt.Sprintf("\n\nError parsing time: %s\nTry again.", err.Error())
-						m.eventTextInput.Reset()
-						m.eventBuilderStage = eventStageTime
-						return m, textinput.Blink
-					}
-
-					// Save event
-					newEvent := Event{
-						Name:       m.currentEventName,
-						DateTime:   t,
-						Repeat:     m.eventFilename,
-						CodePhrase: codePhrase,
-					}
-					if err := saveEventToFile(newEvent); err != nil {
-						m.eventMarkdown += fmt.Sprintf("\n\nError saving event: %s", err.Error())
+					if codePhrase != "" && m.secretPassphrase == "" {
+						m.eventCodePhrase = codePhrase
 						m.eventTextInput.Reset()
-						m.eventBuilderStage = eventStageName
+						m.eventTextInput.Blur()
+						m.newPassphrase = ""
+						m.passphraseInput.Reset()
+						m.passphraseInput.Focus()
+						m.eventBuilderStage = eventStagePassphrase
 						return m, textinput.Blink
 					}
-
-					m.eventMarkdown += "\n\nEvent saved! Press Enter to add another or 'q' to quit."
-					m.eventTextInput.Reset()
-					m.eventBuilderStage = eventStageDone
-					return m, nil
+					return m, m.saveBuiltEvent(codePhrase)
 
 				case eventStageDone:
 					// Reset for another event or quit
@@ -281,93 +292,141 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state == stateRunning || m.state == statePaused {
 			switch msg.String() {
 			case "up":
-				if m.selectedTodo > 0 {
-					m.selectedTodo--
-				}
+				m.flushItemTime()
+				m.selectedTodo = prevVisible(m.currentRoutine().Checklist, m.selectedTodo)
 			case "down":
-				if m.selectedTodo < len(m.currentRoutine().Checklist)-1 {
-					m.selectedTodo++
+				m.flushItemTime()
+				m.selectedTodo = nextVisible(m.currentRoutine().Checklist, m.selectedTodo)
+			case "left":
+				m.flushItemTime()
+				if m.selectedTodo < len(m.currentRoutine().Checklist) {
+					m.selectedTodo = foldItem(m.routines[m.current].Checklist, m.selectedTodo)
+				}
+			case "right":
+				m.flushItemTime()
+				if m.selectedTodo < len(m.currentRoutine().Checklist) {
+					m.selectedTodo = unfoldItem(m.routines[m.current].Checklist, m.selectedTodo)
 				}
 			case " ":
 				if len(m.currentRoutine().Checklist) > 0 && m.selectedTodo >= 0 && m.selectedTodo < len(m.currentRoutine().Checklist) {
-					m.routines[m.current].Checklist[m.selectedTodo].Complete = !m.routines[m.current].Checklist[m.selectedTodo].Complete
+					toggleItem(m.routines[m.current].Checklist, m.selectedTodo)
 				}
 			case "p":
 				if m.state == stateRunning {
-					m.elapsed += time.Since(m.startTime)
-					m.pauseStart = time.Now()
-					m.state = statePausing
-					return m, m.spinner.Tick
+					m.pauseHabit()
+					return m, nil
+				}
+				return m, m.resumeHabit()
+			case "o":
+				return m, m.openOutline()
+			case "f":
+				m.toggleFocusMode()
+				return m, nil
+			case "c":
+				if m.focusMode {
+					m.focusChecklist = !m.focusChecklist
 				}
+				return m, nil
+			case "esc":
+				m.focusMode = false
+				return m, nil
 			case "n":
-				if m.current < len(m.routines)-1 {
-					m.elapsed += time.Since(m.startTime)
-					m.sessions = append(m.sessions, Session{
-						RoutineTitle: m.currentRoutine().Title,
-						Elapsed:      m.elapsed,
-					})
-					m.current++
-					m.state = stateReadyToStart
-					m.elapsed = 0
-					m.selectedTodo = 0
+				m.finishHabit()
+				return m, nil
+			case "x":
+				if m.currentRoutine().Optional {
+					m.skipHabit()
 					return m, nil
 				}
-				*m = m.stopSession()
-				return m, nil
 			case "b":
 				if m.current > 0 {
-					m.elapsed += time.Since(m.startTime)
-					m.sessions = append(m.sessions, Session{
-						RoutineTitle: m.currentRoutine().Title,
-						Elapsed:      m.elapsed,
-					})
-					m.current--
-					m.state = stateReadyToStart
-					m.elapsed = 0
-					m.selectedTodo = 0
+					m.goToHabit(m.current - 1)
 					return m, nil
 				}
 			}
 		}
 
+		if m.state == stateStopped && msg.String() == "x" {
+			csvPath, htmlPath, err := m.exportSession()
+			if err != nil {
+				m.exportMsg = "Export failed: " + err.Error()
+			} else {
+				m.exportMsg = fmt.Sprintf("Exported to %s and %s", csvPath, htmlPath)
+			}
+			return m, nil
+		}
+
+		if m.state == stateScheduledPrompt {
+			switch msg.String() {
+			case "y", "enter":
+				if err := m.openRoutineFile(m.pendingRoutine.FileName); err != nil {
+					m.state = stateCountdown
+					return m, tea.Batch(m.countdownSpinner.Tick, tick())
+				}
+				return m, m.startHabit()
+			case "n", "esc":
+				m.state = stateCountdown
+				return m, tea.Batch(m.countdownSpinner.Tick, tick())
+			}
+		}
+
 		if m.state == stateReadyToStart {
 			switch msg.String() {
+			case "o":
+				return m, m.openOutline()
 			case "y":
-				m.state = stateRunning
-				m.startTime = time.Now()
-				return m, tick()
+				return m, m.startHabit()
 			case "n":
 				m.pauseStart = time.Now()
 				m.state = statePausing
+				m.runHooks(hookPause)
 				return m, m.spinner.Tick
 			}
 		}
 
+	case scheduleTickMsg:
+		now := time.Time(msg)
+		due, ok := dueScheduledRoutine(m.schedules, m.lastScheduleCheck, now)
+		m.lastScheduleCheck = now
+		// A routine that is already running doesn't need offering.
+		if ok && !(m.sessionStarted && due.FileName == m.routineFileName) {
+			m.pendingRoutine = due
+			m.routineDue = true
+			if m.config.Notify {
+				notify("timey", fmt.Sprintf("Time to start %s", due.DisplayName))
+			}
+			if m.state == stateCountdown {
+				m.routineDue = false
+				m.state = stateScheduledPrompt
+			}
+		}
+		return m, scheduleTick()
+
 	case tickMsg:
 		if m.state == stateCountdown {
-			m.countdownRemaining = timeLeftToday()
+			// A routine that came due on another screen is offered once back here.
+			if m.routineDue {
+				m.routineDue = false
+				m.state = stateScheduledPrompt
+				return m, nil
+			}
+			m.refreshCountdowns()
+			m.refreshCountdownEvents()
 			return m, tea.Batch(
 				m.countdownSpinner.Tick,
 				tea.Every(time.Second, func(t time.Time) tea.Msg { return tickMsg(t) }),
 			)
 		}
 		if m.state == stateRunning {
-			if m.elapsed+time.Since(m.startTime) >= m.currentDuration() {
-				m.elapsed += time.Since(m.startTime)
-				m.sessions = append(m.sessions, Session{
-					RoutineTitle: m.currentRoutine().Title,
-					Elapsed:      m.elapsed,
-				})
-				m.current++
-				if m.current >= len(m.routines) {
-					*m = m.stopSession()
-					return m, nil
-				}
-				m.state = stateReadyToStart
-				m.elapsed = 0
-				m.selectedTodo = 0
+			if m.timeUp() {
+				m.finishHabit()
 				return m, nil
 			}
+			m.advanceOverBudgetItem()
+			return m, tick()
+		}
+		if m.state == statePaused {
+			// Keep the paused clock on the running view ticking.
 			return m, tick()
 		}
 
@@ -385,6 +444,22 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 			cmds = append(cmds, m.spinner.Tick)
 		}
+	case quoteRotateMsg:
+		if msg.gen == m.quoteRotateGen && m.state == stateQuotes {
+			m.nextQuote()
+			return m, m.rotateQuoteCmd()
+		}
+		return m, nil
+
+	case apiStateMsg:
+		msg.reply <- m.apiStatus()
+		return m, nil
+
+	case apiCommandMsg:
+		cmd, err := m.apiCommand(msg)
+		msg.reply <- err
+		return m, cmd
+
 	case eventsLoadedMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -392,6 +467,16 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 		m.events = msg.events
+		if m.secretPassphrase != "" {
+			// The file changed under us, e.g. a secret sealed with another passphrase.
+			// Forget the passphrase so the events can be unlocked again.
+			if _, err := unlockEvents(m.events, m.secretPassphrase); err != nil {
+				m.secretPassphrase = ""
+				m.unlockErr = err.Error()
+			}
+		}
+		m.refreshCountdowns()
+		m.refreshCountdownEvents()
 		return m, tick()
 	}
 
@@ -440,3 +525,224 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	return m, tea.Batch(cmds...)
 	}
+
+// saveBuiltEvent parses the event collected by the builder and appends it to the events file.
+func (m *model) saveBuiltEvent(codePhrase string) tea.Cmd {
+	// Parse event time
+	t, err := parseDate(m.currentEventTime)
+	if err != nil {
+		m.eventMarkdown += fmt.Sprintf("\n\nError parsing time: %s\nTry again.", err.Error())
+		m.eventTextInput.Reset()
+		m.eventBuilderStage = eventStageTime
+		return textinput.Blink
+	}
+
+	// Save event
+	newEvent := Event{
+		Name:       m.currentEventName,
+		DateTime:   t,
+		Repeat:     m.eventFilename,
+		CodePhrase: codePhrase,
+	}
+	if err := saveEventToFile(newEvent, m.secretPassphrase); err != nil {
+		m.eventMarkdown += fmt.Sprintf("\n\nError saving event: %s", err.Error())
+		m.eventTextInput.Reset()
+		m.eventBuilderStage = eventStageName
+		return textinput.Blink
+	}
+
+	m.eventMarkdown += "\n\nEvent saved! Press Enter to add another or 'q' to quit."
+	m.eventTextInput.Reset()
+	m.eventBuilderStage = eventStageDone
+	return nil
+}
+
+// updatePassphrase handles keys while a passphrase prompt is focused,
+// either for unlocking sealed events or for sealing a new one.
+func (m *model) updatePassphrase(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.passphraseInput.Blur()
+		m.newPassphrase = ""
+		if m.state == stateAddEvent {
+			m.eventMarkdown += "\n\nEvent discarded: no passphrase given."
+			m.eventBuilderStage = eventStageDone
+			return m, nil
+		}
+		m.unlockErr = ""
+		m.state = stateCountdown
+		return m, tea.Batch(m.countdownSpinner.Tick, tick())
+
+	case "enter":
+		pass := m.passphraseInput.Value()
+		if pass == "" {
+			return m, nil
+		}
+		if !hasSealedEvents(m.events) {
+			// Nothing is sealed yet, so this passphrase is new and a typo would make
+			// the names it seals unrecoverable. Ask for it twice.
+			m.passphraseInput.Reset()
+			if m.newPassphrase == "" {
+				m.newPassphrase = pass
+				m.unlockErr = ""
+				return m, nil
+			}
+			if pass != m.newPassphrase {
+				m.newPassphrase = ""
+				if m.state == stateAddEvent {
+					m.eventMarkdown += "\n\nThe passphrases don't match, try again."
+				} else {
+					m.unlockErr = "the passphrases don't match, try again"
+				}
+				return m, nil
+			}
+			m.newPassphrase = ""
+		}
+		if m.state == stateAddEvent {
+			// Keep the sealed events consistent: a new secret must use the same
+			// passphrase as the ones already on disk.
+			if _, err := unlockEvents(m.events, pass); err != nil {
+				m.eventMarkdown += fmt.Sprintf("\n\n%s, try again.", err.Error())
+				m.passphraseInput.Reset()
+				return m, nil
+			}
+			m.secretPassphrase = pass
+			m.passphraseInput.Blur()
+			m.eventTextInput.Focus()
+			return m, m.saveBuiltEvent(m.eventCodePhrase)
+		}
+
+		plain, err := unlockEvents(m.events, pass)
+		if err != nil {
+			m.unlockErr = err.Error()
+			m.passphraseInput.Reset()
+			return m, nil
+		}
+		m.secretPassphrase = pass
+		if plain > 0 {
+			// Seal secret events that were written in plain text before encryption existed.
+			events := append([]Event(nil), m.events...)
+			for i := range events {
+				if err := sealEvent(&events[i], pass); err != nil {
+					m.unlockErr = err.Error()
+					return m, nil
+				}
+			}
+			if err := writeSealedNames("events/events.md", events); err != nil {
+				m.unlockErr = err.Error()
+				return m, nil
+			}
+			m.events = events
+		}
+		m.passphraseInput.Blur()
+		m.state = stateCountdown
+		return m, tea.Batch(m.countdownSpinner.Tick, tick())
+	}
+
+	var cmd tea.Cmd
+	m.passphraseInput, cmd = m.passphraseInput.Update(msg)
+	return m, cmd
+}
+
+// openRoutineFile loads a routine file, renders it into the viewport and
+// switches to the routine view ready to start.
+func (m *model) openRoutineFile(fileName string) error {
+	path := fmt.Sprintf("routines/%s", fileName)
+	// Show the routine with its includes and variables resolved.
+	lines, err := expandRoutineFile(path, nil)
+	if err != nil {
+		m.viewport.SetContent("Error reading file: " + err.Error())
+		return err
+	}
+	content := strings.Join(lines, "\n")
+	glamourRenderWidth := m.viewport.Width - 2
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(glamourRenderWidth),
+	)
+	if err != nil {
+		m.viewport.SetContent("Error rendering content: " + err.Error())
+	} else {
+		renderedContent, err := renderer.Render(content)
+		if err != nil {
+			m.viewport.SetContent("Error rendering content: " + err.Error())
+		} else {
+			m.viewport.SetContent(renderedContent)
+		}
+	}
+	m.viewport.GotoTop()
+	routines, err := loadRoutines(path)
+	if err != nil {
+		return err
+	}
+	m.routines = habitsForDay(routines, time.Now())
+	m.routineFileName = fileName
+	if fileName == m.pendingRoutine.FileName {
+		m.routineDue = false // Started by hand, no need to offer it any more
+	}
+	m.state = stateRoutineView
+	m.updatePaneSizes()
+	m.elapsed = 0
+	m.current = 0
+	m.selectedTodo = 0
+	m.sessions = []Session{}
+	m.visitStart = time.Time{}
+	m.visitPaused = 0
+	m.totalPaused = 0
+	m.pauses = nil
+	m.sessionStarted = false
+	return nil
+}
+
+// flushItemTime adds the time since the last flush to the selected checklist item.
+func (m *model) flushItemTime() {
+	if m.state != stateRunning || m.current >= len(m.routines) {
+		return
+	}
+	list := m.routines[m.current].Checklist
+	now := time.Now()
+	if m.selectedTodo >= 0 && m.selectedTodo < len(list) {
+		list[m.selectedTodo].Spent += now.Sub(m.itemStart)
+	}
+	m.itemStart = now
+}
+
+// selectedItemSpent returns the time spent on the selected item, including the running stretch.
+func (m model) selectedItemSpent() time.Duration {
+	list := m.currentRoutine().Checklist
+	if m.selectedTodo < 0 || m.selectedTodo >= len(list) {
+		return 0
+	}
+	spent := list[m.selectedTodo].Spent
+	if m.state == stateRunning {
+		spent += time.Since(m.itemStart)
+	}
+	return spent
+}
+
+// advanceOverBudgetItem moves the selection on when the selected item's budget runs out.
+// An item that was already over budget when it was selected stays selected.
+func (m *model) advanceOverBudgetItem() {
+	list := m.currentRoutine().Checklist
+	if m.selectedTodo < 0 || m.selectedTodo >= len(list) {
+		return
+	}
+	// Selecting another item flushes the time and restarts itemStart, so the
+	// item's stored time is what it had when it was selected.
+	before := list[m.selectedTodo].Spent
+	if m.budgetStart.Equal(m.itemStart) {
+		before = m.budgetSpent
+	}
+	spent := m.selectedItemSpent()
+	m.budgetStart, m.budgetSpent = m.itemStart, spent
+
+	budget := list[m.selectedTodo].Budget
+	if budget == 0 || before >= budget || spent < budget {
+		return
+	}
+	if next := nextVisible(list, m.selectedTodo); next != m.selectedTodo {
+		m.flushItemTime()
+		m.selectedTodo = next
+	}
+}
+
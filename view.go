@@ -39,7 +39,28 @@ func (m model) View() string {
 
     case stateAddEvent:
         return renderAddEventView(m)
-        
+
+    case stateUnlockEvents:
+        return renderUnlockView(m)
+
+    case stateCalendar:
+        return renderCalendarView(m)
+
+    case stateAgenda:
+        return renderAgendaView(m)
+
+    case stateScheduledPrompt:
+        return renderScheduledPromptView(m)
+
+    case stateOutline:
+        return renderOutlineView(m)
+
+    case stateStreaks:
+        return renderStreaksView(m)
+
+    case stateQuotesManager:
+        return renderQuotesManagerView(m)
+
     default:
         return "Unknown state"
     }
@@ -47,7 +68,11 @@ func (m model) View() string {
 
 
 func renderQuotesView(m model) string {
-	quote := getRandomQuote(m.quotes)
+	quote := m.currentQuote()
+    favorite := ""
+    if isFavorite(quote) {
+        favorite = " ★"
+    }
     
     return lipgloss.NewStyle().
         Padding(5).
@@ -56,21 +81,24 @@ func renderQuotesView(m model) string {
             lipgloss.NewStyle().
                 Bold(true).
                 Foreground(lipgloss.Color("69")).
-                Render(quote.Text) +
+                Render(quote.Text + favorite) +
                 "\n" +
                 lipgloss.NewStyle().
                     Foreground(lipgloss.Color("241")).
-                    Render("- " + quote.Author) +
+                    Render(quoteAttribution(quote)) +
+                timeOfDayQuoteText(m, quote) +
+                quoteWarningText(m) +
+                quoteErrorText(m) +
                 "\n\n" +
-                controlsStyle.Render("\nhelp • ← → : change view • l: list routines • a: add routine • e: add event • q: quit"),
+                controlsStyle.Render("\nhelp • ← → : change view • n/p: next/previous quote • f: favorite • m: manage quotes • l: list routines • a: add routine • e: add event • q: quit"),
         )
 }
 
 func renderCountdownView(m model) string {
-    h := int(m.countdownRemaining.Hours())
-    mn := int(m.countdownRemaining.Minutes()) % 60
-    s := int(m.countdownRemaining.Seconds()) % 60
-    timeStr := fmt.Sprintf("%02d:%02d:%02d", h, mn, s)
+    var countdowns strings.Builder
+    for _, c := range m.countdowns {
+        countdowns.WriteString(fmt.Sprintf("     %s %s: %s\n", m.countdownSpinner.View(), c.Label, styled.Render(formatCountdown(c.Remaining))))
+    }
 
     // Build events section
     if m.loading {
@@ -85,49 +113,74 @@ func renderCountdownView(m model) string {
     eventsStr.WriteString("Events ")
 	eventsStr.WriteString("\n\n")
 
+    if r, next, ok := nextScheduledRoutine(m.schedules, time.Now()); ok {
+        eventsStr.WriteString(fmt.Sprintf("Next routine: %s at %s, in %s\n\n",
+            eventNameStyle.Render(r.DisplayName), next.Format("Mon 15:04"), eventCountdown(time.Until(next))))
+    }
+
+    if m.countdownViewport.Height > 0 {
+        eventsStr.WriteString(m.countdownViewport.View())
+    } else {
+        eventsStr.WriteString(renderEventList(m))
+    }
+
+    help := "\nhelp • ← → : switch view • l: list routines • a: add routine • e: add event • c: calendar • g: agenda • s: streaks • ↑/↓: scroll • m: more • q: quit"
+    if hasLockedEvents(m.events) {
+        if m.unlockErr != "" {
+            eventsStr.WriteString(eventNameStyle.Render("Secret events were locked again: "+m.unlockErr) + "\n")
+        }
+        help = "\nhelp • ← → : switch view • l: list routines • a: add routine • e: add event • c: calendar • g: agenda • s: streaks • u: unlock secrets • ↑/↓: scroll • m: more • q: quit"
+    }
+
+    return fmt.Sprintf("\n%s \n%s%s %s\n",
+        m.countdownHeader(),
+        countdowns.String(),
+        eventsStr.String(),
+        controlsStyle.Render(help),)
+}
+
+// countdownHeader shows the greeting bars with any goal bars beside them.
+func (m model) countdownHeader() string {
+    greeting := eventSeparatorStyle.Render(m.countdownGreetText)
+    if len(m.goals) == 0 {
+        return greeting
+    }
+    return lipgloss.JoinHorizontal(lipgloss.Top, greeting, " ", goalsBoxStyle.Render(renderGoals(m.goals)))
+}
+
+// renderEventList lists events by next occurrence, grouped into Today, This week and Later.
+func renderEventList(m model) string {
+    var b strings.Builder
 	if len(m.events) == 0 {
-		eventsStr.WriteString("No events found. Press 'a' to add one.\n")
-	} else {
-		for _, event := range m.events {
-			now := time.Now()
-			nextOccurrence := getNextOccurrence(event, now)
-			duration := time.Until(nextOccurrence)
-
-			displayName := event.Name
-			if event.CodePhrase != "" {
-				displayName = event.CodePhrase
-			}
-
-			var countdown string
-			days := int(duration.Hours() / 24)
-			hours := int(duration.Hours()) % 24
-			minutes := int(duration.Minutes()) % 60
-			seconds := int(duration.Seconds()) % 60
-
-			if days > 0 {
-				countdown = fmt.Sprintf("%d d ", days)
-			} else if hours > 0 {
-				countdown = fmt.Sprintf("%d h %d m ", hours, minutes)
-			} else if minutes > 0 {
-				countdown = fmt.Sprintf("%d m %d s", minutes, seconds)
-			} else if seconds > 0 {
-				countdown = fmt.Sprintf("%d s", seconds)
-			} else {
-				countdown = fmt.Sprintf("%d d ago", days)
-			}
-
-			eventLine := fmt.Sprintf("%s: %s", eventNameStyle.Render(displayName), countdown)
-			eventsStr.WriteString("• " + eventLine + "\n")
-		}
+		b.WriteString("No events found. Press 'e' to add one.\n")
+		return b.String()
 	}
 
+    now := time.Now()
+    upcoming := upcomingEvents(m.events, now)
+    hidden := 0
+    if !m.showAllEvents && m.config.MaxEvents > 0 && len(upcoming) > m.config.MaxEvents {
+        hidden = len(upcoming) - m.config.MaxEvents
+        upcoming = upcoming[:m.config.MaxEvents]
+    }
 
-    return fmt.Sprintf("\n%s \n     %s time left today: %s\n%s %s\n",
-        eventSeparatorStyle.Render(m.countdownGreetText),
-        m.countdownSpinner.View(),
-        styled.Render(timeStr),
-        eventsStr.String(),
-        controlsStyle.Render("\nhelp • ← → : switch view • l: list routines • a: add routine • e: add event • q: quit"),)
+    group := ""
+    for _, u := range upcoming {
+        if g := eventGroup(u.Next, now, m.config.WeekStart); g != group {
+            if group != "" {
+                b.WriteString("\n")
+            }
+            group = g
+            b.WriteString(calendarHeaderStyle.Render(group) + "\n")
+        }
+        countdown := eventCountdown(u.Next.Sub(now))
+        eventLine := fmt.Sprintf("%s: %s", eventNameStyle.Render(eventDisplayName(u.Event)), countdown)
+        b.WriteString("• " + eventLine + "\n")
+    }
+    if hidden > 0 {
+        b.WriteString(blurredStyle.Render(fmt.Sprintf("+%d more", hidden)) + "\n")
+    }
+    return b.String()
 }
 
 func renderFilePickerView(m model) string {
@@ -156,33 +209,79 @@ func renderRoutineView(m model) string {
     }
 
     leftPane := lipgloss.NewStyle().Width(listWidth).Render(m.fileList.View())
-    rightPaneContent := m.viewport.View() + summaryHelpStyle("\n help • ↑/↓ : scroll • enter: start routine • q: back to list\n")
+    help := "\n help • ↑/↓ : scroll • enter: start routine • q: back to list\n"
+    if len(m.routines) == 0 {
+        help = "\n" + eventNameStyle.Render(" No habits in this routine run today.") + "\n" + summaryHelpStyle("\n help • ↑/↓ : scroll • q: back to list\n")
+    } else {
+        help = summaryHelpStyle(help)
+    }
+    rightPaneContent := m.viewport.View() + help
     rightPane := lipgloss.NewStyle().Width(contentWidth).Render(rightPaneContent)
 
     return lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
 }
 
 func renderPausingView(m model) string {
-    return fmt.Sprintf("\n\n%s Paused: %s\n\n%s",
+    quote := ""
+    if q, ok := m.breakQuote(); ok {
+        quote = renderContextQuote(q, m.width-4) + "\n\n"
+    }
+    return fmt.Sprintf("\n\n%s Paused: %s\n\n%s%s",
         m.spinner.View(),
         pauseTimerStyle.Render(time.Since(m.pauseStart).Truncate(time.Second).String()),
+        quote,
         pausedControlsStyle.Render("s: resume • q: quit"))
 }
 
+// renderContextQuote shows a quote in the session screens, smaller than on the quotes screen.
+func renderContextQuote(q Quote, width int) string {
+    style := contextQuoteStyle
+    if width > 0 {
+        style = style.Width(width)
+    }
+    return style.Render("“"+q.Text+"”") + "\n" + blurredStyle.Render(quoteAttribution(q))
+}
+
+func renderScheduledPromptView(m model) string {
+    return fmt.Sprintf("\n\nIt's %s, time for %s.\n\nStart it now?\n\ny: yes  •  n: not now\n",
+        time.Now().Format("15:04"), eventNameStyle.Render(m.pendingRoutine.DisplayName))
+}
+
 func renderReadyToStartView() string {
-    return "\n\nReady to start the next one?\n\ny: yes  •  n: no\n"
+    return "\n\nReady to start the next one?\n\ny: yes  •  n: no  •  o: outline\n"
 }
 
 func renderStoppedView(m model) string {
-    return m.viewport.View() + summaryHelpStyle("\nhelp • ↑/↓: scroll • q: menu\n")
+    help := "\nhelp • ↑/↓: scroll • x: export CSV/HTML • q: menu\n"
+    if m.exportMsg != "" {
+        help = "\n" + m.exportMsg + help
+    }
+    return m.viewport.View() + summaryHelpStyle(help)
 }
 
 func renderRunningView(m model) string {
+    if m.focusMode {
+        return renderFocusView(m)
+    }
     var b strings.Builder
     r := m.currentRoutine()
     dur := m.currentDuration()
 
-    b.WriteString(routineTitleStyle.Render(r.Title) + "\n")
+    if q, ok := m.runningQuote(); ok {
+        b.WriteString(renderContextQuote(q, m.width-4) + "\n\n")
+    }
+
+    title := r.Title
+    if r.Optional {
+        title += blurredStyle.Render(" (optional)")
+    }
+    b.WriteString(routineTitleStyle.Render(title) + "\n")
+    for _, note := range r.Notes {
+        b.WriteString(habitNoteStyle.Render(note) + "\n")
+    }
+    if len(r.Notes) > 0 {
+        b.WriteString("\n")
+    }
 
     currentRoutineElapsed := getCurrentRoutineElapsed(m)
     percent := getProgressPercentage(currentRoutineElapsed, dur)
@@ -198,11 +297,81 @@ func renderRunningView(m model) string {
         currentRoutineElapsed.Truncate(time.Second), dur))
 
     renderChecklist(&b, r.Checklist, m.selectedTodo)
-    b.WriteString(controlsStyle.Render("\nhelp • s: start • p: pause • n: next • b: back • ↑/↓: select • space: toggle todo • q: quit\n"))
+    if m.selectedTodo >= 0 && m.selectedTodo < len(r.Checklist) && r.Checklist[m.selectedTodo].Budget > 0 {
+        item := r.Checklist[m.selectedTodo]
+        spent := m.selectedItemSpent()
+        itemPercent := getProgressPercentage(spent, item.Budget)
+        b.WriteString(fmt.Sprintf("\n%s  %s / %s\n", m.itemProgress.ViewAs(itemPercent),
+            spent.Truncate(time.Second), item.Budget))
+    }
+    help := "\nhelp • s: resume • p: pause/resume • n: next • b: back • o: outline • f: focus • ↑/↓: select • ←/→: fold • space: toggle todo • q: quit\n"
+    if r.Optional {
+        help = "\nhelp • s: resume • p: pause/resume • n: next • b: back • x: skip • o: outline • f: focus • ↑/↓: select • ←/→: fold • space: toggle todo • q: quit\n"
+    }
+    b.WriteString(controlsStyle.Render(help))
 
     return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
 }
 
+// renderFocusView fills the terminal with the time left on the current habit in
+// big digits, scaled to the window. The checklist is shown only when toggled with c.
+func renderFocusView(m model) string {
+    r := m.currentRoutine()
+    dur := m.currentDuration()
+    elapsed := getCurrentRoutineElapsed(m)
+    width, height := m.width, m.height
+    if width == 0 || height == 0 {
+        width, height = 80, 24 // Before the first WindowSizeMsg
+    }
+
+    var checklist strings.Builder
+    if m.focusChecklist && len(r.Checklist) > 0 {
+        renderChecklist(&checklist, r.Checklist, m.selectedTodo)
+    }
+    listHeight := 0
+    if checklist.Len() > 0 {
+        listHeight = lipgloss.Height(checklist.String()) + 1
+    }
+
+    clock := formatClock(dur - elapsed)
+    digits := focusDigitStyle
+    if m.state == statePaused {
+        digits = focusPausedDigitStyle
+    }
+    var big string
+    if scale := bigClockScale(clock, width-4, height-focusChrome-listHeight); scale > 0 {
+        big = digits.Render(bigText(clock, scale))
+    } else {
+        big = digits.Bold(true).Render(clock) // Too small for big digits
+    }
+
+    bar := m.progress
+    bar.Width = max(min(lipgloss.Width(big), width-4), 10)
+    status := fmt.Sprintf("%s / %s", elapsed.Truncate(time.Second), dur)
+    if m.state == statePaused {
+        status = fmt.Sprintf("Paused: %s", time.Since(m.pauseStart).Truncate(time.Second))
+    }
+
+    parts := []string{
+        routineTitleStyle.Render(r.Title),
+        big,
+        "",
+        bar.ViewAs(getProgressPercentage(elapsed, dur)),
+        blurredStyle.Render(status),
+    }
+    if listHeight > 0 {
+        parts = append(parts, "", lipgloss.NewStyle().Align(lipgloss.Left).Render(checklist.String()))
+    }
+    help := "help • f/esc: leave focus • c: checklist • p: pause/resume • n: next • q: quit"
+    if m.focusChecklist {
+        help = "help • f/esc: leave focus • c: hide checklist • ↑/↓: select • space: toggle todo • p: pause/resume • n: next • q: quit"
+    }
+    parts = append(parts, controlsStyle.Width(min(lipgloss.Width(help), width-4)).Align(lipgloss.Center).Render(help))
+
+    return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+        lipgloss.JoinVertical(lipgloss.Center, parts...))
+}
+
 func renderAddRoutineView(m model) string {
     var s strings.Builder
     s.WriteString(m.viewport.View())
@@ -218,10 +387,7 @@ func renderAddRoutineView(m model) string {
 
 
 func getCurrentRoutineElapsed(m model) time.Duration {
-    if m.state == stateRunning {
-        return m.elapsed + time.Since(m.startTime)
-    }
-    return m.elapsed
+    return m.habitElapsed()
 }
 
 func getProgressPercentage(elapsed, duration time.Duration) float64 {
@@ -239,11 +405,24 @@ func renderProgressBar(m model, percent float64) string {
 
 func renderChecklist(b *strings.Builder, checklist []ChecklistItem, selectedTodo int) {
     for i, item := range checklist {
+        if !isVisible(checklist, i) {
+            continue
+        }
         checked := " "
         if item.Complete {
             checked = "x"
         }
-        itemString := fmt.Sprintf("[ %s ] %s", checked, item.Text)
+        fold := "  "
+        if hasChildren(checklist, i) {
+            fold = "▾ "
+            if item.Collapsed {
+                fold = "▸ "
+            }
+        }
+        itemString := fmt.Sprintf("%s%s[ %s ] %s", strings.Repeat("    ", item.Depth), fold, checked, item.Text)
+        if item.Budget > 0 {
+            itemString += blurredStyle.Render(fmt.Sprintf(" (%s)", item.Budget))
+        }
         if i == selectedTodo {
             b.WriteString(focusedChecklistStyle.Render(itemString))
         } else {
@@ -258,9 +437,369 @@ func renderAddEventView(m model) string {
     var s strings.Builder
     s.WriteString(m.eventViewport.View())
     s.WriteString("\n\n")
-    s.WriteString(m.eventTextInput.View())
+    if m.eventBuilderStage == eventStagePassphrase {
+        s.WriteString(m.passphraseInput.View())
+        if m.newPassphrase != "" {
+            s.WriteString("\n\n" + focusedStyle.Render("Type the passphrase again to confirm it."))
+        }
+        s.WriteString(controlsStyle.Render("\nThis event has a code phrase, its name will be stored encrypted. enter: save • esc: discard"))
+    } else {
+        s.WriteString(m.eventTextInput.View())
+    }
     if m.eventBuilderStage == eventStageDone {
         s.WriteString(focusedStyle.Render("\n Event saved! Press 'enter' to add another or 'q' to quit.\n"))
     }
     return s.String()
-}
\ No newline at end of file
+}
+
+func renderUnlockView(m model) string {
+    var s strings.Builder
+    s.WriteString(eventSeparatorStyle.Render("Unlock secret events"))
+    s.WriteString("\n\n")
+    s.WriteString(m.passphraseInput.View())
+    if m.newPassphrase != "" {
+        s.WriteString("\n\n" + focusedStyle.Render("This passphrase will seal your secret events. Type it again to confirm it."))
+    }
+    if m.unlockErr != "" {
+        s.WriteString("\n\n" + eventNameStyle.Render("Error: "+m.unlockErr))
+    }
+    s.WriteString(controlsStyle.Render("\nhelp • enter: unlock • esc: back"))
+    return lipgloss.NewStyle().Padding(1, 2).Render(s.String())
+}
+
+func renderCalendarView(m model) string {
+    var b strings.Builder
+    today := startOfDay(time.Now())
+    sel := m.calendarDay
+    first := time.Date(sel.Year(), sel.Month(), 1, 0, 0, 0, 0, sel.Location())
+    weekStart := m.config.WeekStart
+    gridStart := startOfWeek(first, weekStart)
+    gridEnd := gridStart.AddDate(0, 0, 42)
+
+    // Mark every day in the visible grid that has something scheduled.
+    busy := make(map[string]bool)
+    for _, item := range agendaBetween(m.events, m.schedules, m.scheduledHabits, gridStart, gridEnd) {
+        busy[item.When.Format("2006-01-02")] = true
+    }
+
+    b.WriteString(calendarHeaderStyle.Render(sel.Format("January 2006")) + "\n\n")
+    for d := 0; d < 7; d++ {
+        b.WriteString(fmt.Sprintf(" %-3s", time.Weekday((int(weekStart)+d)%7).String()[:2]))
+    }
+    b.WriteString("\n")
+
+    for day := gridStart; day.Before(gridEnd); day = day.AddDate(0, 0, 1) {
+        mark := " "
+        if busy[day.Format("2006-01-02")] {
+            mark = "•"
+        }
+        cell := fmt.Sprintf("%2d%s", day.Day(), mark)
+        switch {
+        case sameDay(day, sel):
+            cell = calendarSelectedStyle.Render(cell)
+        case sameDay(day, today):
+            cell = calendarTodayStyle.Render(cell)
+        case day.Month() != sel.Month():
+            cell = calendarOtherStyle.Render(cell)
+        case mark != " ":
+            cell = calendarBusyStyle.Render(cell)
+        }
+        b.WriteString(" " + cell)
+        if day.Weekday() == (weekStart+6)%7 {
+            b.WriteString("\n")
+        }
+    }
+
+    b.WriteString("\n" + calendarHeaderStyle.Render(sel.Format("Monday, 2 January")) + "\n")
+    items := agendaBetween(m.events, m.schedules, m.scheduledHabits, sel, sel.AddDate(0, 0, 1))
+    if len(items) == 0 {
+        b.WriteString("Nothing scheduled.\n")
+    }
+    for _, item := range items {
+        b.WriteString(renderAgendaItem(item))
+    }
+
+    b.WriteString(controlsStyle.Render("\nhelp • ←/→: day • ↑/↓: week • [ ]: month • t: today • enter: agenda • q: back"))
+    return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
+
+func renderAgendaItem(item agendaItem) string {
+    title := eventNameStyle.Render(item.Title)
+    switch item.Kind {
+    case "habit":
+        title = routineTitleStyle.UnsetPaddingBottom().Render(item.Title)
+    case "routine":
+        title = calendarHeaderStyle.Render(item.Title)
+    }
+    return fmt.Sprintf("  %s  %s\n", eventTimeStyle.Render(item.When.Format("15:04")), title)
+}
+
+// renderAgendaContent lists everything scheduled from the selected day onwards, grouped by day.
+func renderAgendaContent(m model) string {
+    var b strings.Builder
+    today := time.Now()
+    from := m.calendarDay
+    items := agendaBetween(m.events, m.schedules, m.scheduledHabits, from, from.AddDate(0, 0, agendaDays))
+
+    if len(items) == 0 {
+        return fmt.Sprintf("Nothing scheduled in the next %d days.\n", agendaDays)
+    }
+    var last time.Time
+    for _, item := range items {
+        if last.IsZero() || !sameDay(item.When, last) {
+            header := item.When.Format("Mon, 2 Jan 2006")
+            if sameDay(item.When, today) {
+                header = calendarTodayStyle.Render(header + " (today)")
+            } else {
+                header = calendarHeaderStyle.Render(header)
+            }
+            if !last.IsZero() {
+                b.WriteString("\n")
+            }
+            b.WriteString(header + "\n")
+            last = item.When
+        }
+        b.WriteString(renderAgendaItem(item))
+    }
+    return b.String()
+}
+
+func renderAgendaView(m model) string {
+    return m.agendaViewport.View() + summaryHelpStyle("\nhelp • ↑/↓: scroll • t: today • c: calendar • q: back\n")
+}
+
+func renderOutlineView(m model) string {
+    var b strings.Builder
+    b.WriteString(routineTitleStyle.Render("Session outline") + "\n")
+
+    for i, r := range m.routines {
+        status := "[ ]"
+        switch {
+        case i == m.current:
+            status = "[>]"
+        case r.Status == habitDone:
+            status = "[x]"
+        case r.Status == habitSkipped:
+            status = "[-]"
+        }
+        line := fmt.Sprintf("%s %s  %s", status, r.Title, blurredStyle.Render(r.Time))
+        spent := r.Elapsed
+        if i == m.current {
+            spent = m.habitElapsed()
+        }
+        if spent > 0 {
+            line += blurredStyle.Render(fmt.Sprintf("  spent %s", spent.Truncate(time.Second)))
+        }
+        if i == m.outlineCursor {
+            b.WriteString(focusedChecklistStyle.Render(line))
+        } else {
+            b.WriteString(checklistStyle.Render(line))
+        }
+        b.WriteString("\n")
+    }
+
+    if m.outlineInserting {
+        b.WriteString("\n" + m.outlineInput.View())
+        if m.outlineErr != "" {
+            b.WriteString("\n" + eventNameStyle.Render(m.outlineErr))
+        }
+        b.WriteString(controlsStyle.Render("\nhelp • enter: insert after selected • esc: cancel"))
+    } else {
+        b.WriteString(controlsStyle.Render("\nhelp • ↑/↓: select • enter: jump to habit • x: skip • m: move later • i: insert habit • esc: back"))
+    }
+    return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
+
+// renderHeatmap draws a GitHub-style grid of the last weeks, one column per week
+// starting on weekStart, shaded by how many completions each day had.
+func renderHeatmap(s streakStats, weeks int, now time.Time, weekStart time.Weekday) string {
+    today := startOfDay(now)
+    start := startOfWeek(today, weekStart).AddDate(0, 0, -7*(weeks-1))
+
+    most := 0
+    for _, n := range s.Days {
+        most = max(most, n)
+    }
+    level := func(n int) int {
+        if n == 0 || most == 0 {
+            return 0
+        }
+        top := len(heatmapLevels) - 1
+        return (n*top + most - 1) / most
+    }
+
+    var b strings.Builder
+    // Month labels above the first week of each month.
+    labels := []rune(strings.Repeat(" ", weeks*2))
+    for w := 0; w < weeks; w++ {
+        day := start.AddDate(0, 0, 7*w)
+        if w == 0 || day.AddDate(0, 0, -7).Month() != day.Month() {
+            name := []rune(day.Format("Jan"))
+            if w*2+len(name) <= len(labels) {
+                copy(labels[w*2:], name)
+            }
+        }
+    }
+    b.WriteString("     " + calendarOtherStyle.Render(string(labels)) + "\n")
+
+    for d := 0; d < 7; d++ {
+        label := "    "
+        if d%2 == 1 {
+            label = time.Weekday((int(weekStart)+d)%7).String()[:3] + " "
+        }
+        b.WriteString(" " + calendarOtherStyle.Render(label))
+        for w := 0; w < weeks; w++ {
+            day := start.AddDate(0, 0, 7*w+d)
+            if day.After(today) {
+                b.WriteString("  ")
+                continue
+            }
+            b.WriteString(heatmapLevels[level(s.Days[dayKey(day)])].Render("■") + " ")
+        }
+        b.WriteString("\n")
+    }
+
+    b.WriteString("     " + calendarOtherStyle.Render("less "))
+    for _, style := range heatmapLevels {
+        b.WriteString(style.Render("■") + " ")
+    }
+    b.WriteString(calendarOtherStyle.Render("more") + "\n")
+    return b.String()
+}
+
+func renderStreaksView(m model) string {
+    var b strings.Builder
+    b.WriteString(calendarHeaderStyle.Render("Streaks") + "\n\n")
+
+    if m.streaksErr != nil {
+        b.WriteString(fmt.Sprintf("Error reading logs: %v\n", m.streaksErr))
+    }
+    if len(m.streaks) == 0 || m.streaks[0].First.IsZero() {
+        b.WriteString("No sessions logged yet. Finish a routine to start a streak.\n")
+        b.WriteString(controlsStyle.Render("\nhelp • q: back"))
+        return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+    }
+
+    weeks := 53
+    if m.width > 0 {
+        weeks = min(weeks, max((m.width-12)/2, 4))
+    }
+    selected := m.streaks[m.streakCursor]
+    b.WriteString(eventNameStyle.Render(selected.Name) + "\n")
+    b.WriteString(renderHeatmap(selected, weeks, time.Now(), m.config.WeekStart) + "\n")
+
+    b.WriteString(fmt.Sprintf("  %-*s %8s %8s %6s\n", timelineLabelWidth, "", "current", "longest", "rate"))
+    lastKind := ""
+    for i, s := range m.streaks {
+        if s.Kind != lastKind && s.Kind != "all" {
+            b.WriteString("\n" + calendarOtherStyle.Render(strings.Title(s.Kind)+"s") + "\n")
+        }
+        lastKind = s.Kind
+        line := fmt.Sprintf("%-*s %7dd %7dd %5.0f%%", timelineLabelWidth, truncateLabel(s.Name, timelineLabelWidth), s.Current, s.Longest, s.Rate*100)
+        if i == m.streakCursor {
+            b.WriteString(focusedStyle.Render("> "+line) + "\n")
+        } else {
+            b.WriteString("  " + line + "\n")
+        }
+    }
+
+    b.WriteString(controlsStyle.Render("\nhelp • ↑/↓: select • q: back"))
+    return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
+
+// quoteWarningText tells how many lines of quotes.md were skipped, if any.
+func quoteWarningText(m model) string {
+    switch m.quoteWarnings {
+    case 0:
+        return ""
+    case 1:
+        return "\n\n" + eventNameStyle.Render("⚠ 1 line in quotes.md could not be read as a quote")
+    }
+    return "\n\n" + eventNameStyle.Render(fmt.Sprintf("⚠ %d lines in quotes.md could not be read as quotes", m.quoteWarnings))
+}
+
+// timeOfDayQuoteText shows the quote for this part of the day under the one on screen.
+func timeOfDayQuoteText(m model, shown Quote) string {
+    q, ok := timeOfDayQuote(m.quotes, time.Now())
+    if !ok || quoteKey(q) == quoteKey(shown) {
+        return ""
+    }
+    return "\n\n" + renderContextQuote(q, min(max(m.width-14, 20), 70))
+}
+
+// quoteErrorText shows why the last change to quotes.md failed, if it did.
+func quoteErrorText(m model) string {
+    if m.quoteErr == "" {
+        return ""
+    }
+    return "\n\n" + eventNameStyle.Render(m.quoteErr)
+}
+
+// quoteAttribution renders "- Author, Source" for a quote.
+func quoteAttribution(q Quote) string {
+    s := "- " + q.Author
+    if q.Source != "" {
+        s += ", " + q.Source
+    }
+    return s
+}
+
+func renderQuotesManagerView(m model) string {
+    var b strings.Builder
+    b.WriteString(calendarHeaderStyle.Render(fmt.Sprintf("Quotes (%d)", len(m.quotes))) + "\n\n")
+
+    if m.quoteEditing {
+        title := "New quote"
+        if m.quoteEditIndex >= 0 {
+            title = fmt.Sprintf("Edit quote %d", m.quoteEditIndex+1)
+        }
+        b.WriteString(eventNameStyle.Render(title) + "\n\n")
+        for _, input := range m.quoteInputs {
+            b.WriteString(input.View() + "\n")
+        }
+        if m.quoteErr != "" {
+            b.WriteString("\n" + eventNameStyle.Render(m.quoteErr) + "\n")
+        }
+        b.WriteString(controlsStyle.Render("\nhelp • tab/↑/↓: field • enter: next field, save on the last • ctrl+s: save • esc: cancel"))
+        return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+    }
+
+    if m.quoteWarnings > 0 {
+        b.WriteString(strings.TrimPrefix(quoteWarningText(m), "\n\n") + "\n")
+        b.WriteString(blurredStyle.Render("Fix them in quotes.md before adding, editing or deleting quotes here.") + "\n\n")
+    }
+    if len(m.quotes) == 0 {
+        b.WriteString("No quotes yet. Press 'a' to add one.\n")
+    }
+    // Keep the cursor in view when there are more quotes than fit on screen.
+    visible := len(m.quotes)
+    if m.height > 0 {
+        visible = max((m.height-10)/2, 3)
+    }
+    first := max(0, min(m.quoteCursor-visible/2, len(m.quotes)-visible))
+    last := min(first+visible, len(m.quotes))
+    width := max(m.width-12, 20)
+
+    for i := first; i < last; i++ {
+        q := m.quotes[i]
+        text := truncateLabel(fmt.Sprintf("%d. \"%s\"", i+1, q.Text), width)
+        meta := quoteAttribution(q)
+        for _, tag := range q.Tags {
+            meta += " #" + tag
+        }
+        if i == m.quoteCursor {
+            b.WriteString(focusedStyle.Render("> "+text) + "\n")
+        } else {
+            b.WriteString("  " + text + "\n")
+        }
+        b.WriteString("     " + blurredStyle.Render(truncateLabel(meta, width)) + "\n")
+    }
+
+    if m.quoteConfirmDelete {
+        b.WriteString("\n" + eventNameStyle.Render(fmt.Sprintf("Delete quote %d? y: yes • any other key: no", m.quoteCursor+1)) + "\n")
+    } else if m.quoteErr != "" {
+        b.WriteString("\n" + eventNameStyle.Render(m.quoteErr) + "\n")
+    }
+    b.WriteString(controlsStyle.Render("\nhelp • ↑/↓: select • a: add • e: edit • d: delete • q: back"))
+    return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}